@@ -0,0 +1,288 @@
+package adpause
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// The settings file holds which rewards to pause per channel; the state file holds the rewards paused for an ad
+// that's running, with how they were before, so a restart or a crash can still put them back.
+// Pause and Restore hold the state file's store.Lock from reading the rewards to the last change, since start-ad, the
+// API server, and ad-pause watch can all see the same ad.
+const (
+	settingsFile = "ad-pause.json"
+	stateFile    = "ad-pause-state.json"
+)
+
+// PausedReward is a reward paused for an ad, and whether it was already paused before.
+type PausedReward struct {
+	Title     string `json:"title"`
+	WasPaused bool   `json:"was_paused"`
+}
+
+// Snapshot is a channel's rewards paused for an ad, and when the (last) ad ends.
+type Snapshot struct {
+	Until   time.Time               `json:"until"`
+	Rewards map[string]PausedReward `json:"rewards"` // By reward ID
+}
+
+// Rewards returns the reward IDs or titles to pause during ads on a channel.
+func Rewards(channelID string) ([]string, error) {
+	settings := make(map[string][]string)
+	if err := store.Load(settingsFile, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings[channelID], nil
+}
+
+// AddRewards adds reward IDs or titles to pause during ads on a channel; ones already there are skipped.
+// Returns the full list and error.
+func AddRewards(channelID string, rewards []string) ([]string, error) {
+	settings := make(map[string][]string)
+	err := store.Update(settingsFile, &settings, func() error {
+		for _, reward := range rewards {
+			if indexOf(settings[channelID], reward) == -1 {
+				settings[channelID] = append(settings[channelID], reward)
+			}
+		}
+		return nil
+	})
+
+	return settings[channelID], err
+}
+
+// RemoveRewards stops pausing reward IDs or titles during ads on a channel.
+// Returns the full list and error.
+func RemoveRewards(channelID string, rewards []string) ([]string, error) {
+	settings := make(map[string][]string)
+	err := store.Update(settingsFile, &settings, func() error {
+		for _, reward := range rewards {
+			i := indexOf(settings[channelID], reward)
+			if i == -1 {
+				return fmt.Errorf("%s isn't paused during ads", reward)
+			}
+			settings[channelID] = append(settings[channelID][:i], settings[channelID][i+1:]...)
+		}
+		if len(settings[channelID]) == 0 {
+			delete(settings, channelID)
+		}
+		return nil
+	})
+
+	return settings[channelID], err
+}
+
+func indexOf(list []string, reward string) int {
+	for i, entry := range list {
+		if strings.EqualFold(entry, reward) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetSnapshot returns the channel's rewards paused for an ad, and whether there's an ad pause going on.
+func GetSnapshot(channelID string) (Snapshot, bool, error) {
+	snapshots := make(map[string]Snapshot)
+	if err := store.Load(stateFile, &snapshots); err != nil {
+		return Snapshot{}, false, err
+	}
+
+	snapshot, ok := snapshots[channelID]
+	return snapshot, ok, nil
+}
+
+// Pause pauses the channel's configured rewards for an ad of the given length, remembering which were already
+// paused. An ad starting while another pause is going on only pushes the end back, so the first ad's snapshot (the
+// state before any ad) is what gets restored; an entry already in the snapshot is never replaced.
+// The snapshot is saved before any reward is paused, so a crash part way through can still be restored.
+// Only rewards msc created can be paused; others are reported and skipped.
+// Returns how many rewards it paused and error.
+func Pause(c helix.Client, channelID string, length time.Duration) (int, error) {
+	configured, err := Rewards(channelID)
+	if err != nil || len(configured) == 0 {
+		return 0, err
+	}
+
+	unlock, err := store.Lock(stateFile)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	manageable, err := twitch.GetManageableRewards(c, channelID)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshots := make(map[string]Snapshot)
+	if err := store.Load(stateFile, &snapshots); err != nil {
+		return 0, err
+	}
+
+	snapshot := snapshots[channelID]
+	if snapshot.Rewards == nil {
+		snapshot.Rewards = make(map[string]PausedReward)
+	}
+
+	var toPause []helix.ChannelCustomReward
+	for _, entry := range configured {
+		reward, found := findReward(manageable, entry)
+		if !found {
+			fmt.Printf("Reward %s wasn't found, or wasn't created by msc so it can't be paused.\n", entry)
+			continue
+		}
+
+		if _, ok := snapshot.Rewards[reward.ID]; !ok {
+			snapshot.Rewards[reward.ID] = PausedReward{Title: reward.Title, WasPaused: reward.IsPaused}
+		}
+		if !reward.IsPaused {
+			toPause = append(toPause, reward)
+		}
+	}
+
+	if until := time.Now().Add(length); until.After(snapshot.Until) {
+		snapshot.Until = until
+	}
+	snapshots[channelID] = snapshot
+
+	if err := store.Save(stateFile, snapshots); err != nil {
+		return 0, err
+	}
+
+	paused := 0
+	for _, reward := range toPause {
+		pause := true
+		if _, err := twitch.UpdateReward(c, channelID, reward.ID, twitch.RewardUpdate{IsPaused: &pause}); err != nil {
+			fmt.Printf("Failed to pause %s: %s\n", reward.Title, err)
+			continue
+		}
+		paused++
+	}
+
+	return paused, nil
+}
+
+func findReward(rewards []helix.ChannelCustomReward, entry string) (helix.ChannelCustomReward, bool) {
+	for _, reward := range rewards {
+		if reward.ID == entry {
+			return reward, true
+		}
+	}
+	for _, reward := range rewards {
+		if strings.EqualFold(reward.Title, entry) {
+			return reward, true
+		}
+	}
+	return helix.ChannelCustomReward{}, false
+}
+
+// Restore puts the channel's rewards paused for an ad back how they were: rewards that weren't paused before are
+// unpaused, and ones that were stay paused. Rewards that fail to unpause are kept in the snapshot for another try.
+// Returns error.
+func Restore(c helix.Client, channelID string) error {
+	unlock, err := store.Lock(stateFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	snapshots := make(map[string]Snapshot)
+	if err := store.Load(stateFile, &snapshots); err != nil {
+		return err
+	}
+
+	snapshot, ok := snapshots[channelID]
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, 0, len(snapshot.Rewards))
+	for id := range snapshot.Rewards {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		reward := snapshot.Rewards[id]
+		if !reward.WasPaused {
+			unpause := false
+			if _, err := twitch.UpdateReward(c, channelID, id, twitch.RewardUpdate{IsPaused: &unpause}); err != nil {
+				fmt.Printf("Failed to unpause %s: %s\n", reward.Title, err)
+				continue
+			}
+		}
+		delete(snapshot.Rewards, id)
+	}
+
+	if len(snapshot.Rewards) != 0 {
+		snapshots[channelID] = snapshot
+		if err := store.Save(stateFile, snapshots); err != nil {
+			return err
+		}
+		return fmt.Errorf("%d rewards couldn't be unpaused; run msc reward ad-pause restore to try again", len(snapshot.Rewards))
+	}
+
+	delete(snapshots, channelID)
+	return store.Save(stateFile, snapshots)
+}
+
+// Run pauses the channel's configured rewards for an ad of the given length, waits for it to end, and restores them.
+// It waits longer if another ad pushes the end back, and leaves the restore to whichever Run sees the last ad end.
+// Closing stop restores the rewards right away. A nil stop never fires.
+// Returns error.
+func Run(c helix.Client, channelID string, length time.Duration, stop <-chan struct{}) error {
+	paused, err := Pause(c, channelID, length)
+	if err != nil {
+		return err
+	}
+
+	snapshot, ok, err := GetSnapshot(channelID)
+	if err != nil || !ok {
+		return err // Nothing configured
+	}
+	fmt.Printf("Paused %d rewards for the ad; they'll be restored at %s.\n", paused, snapshot.Until.Local().Format("15:04:05"))
+
+	for {
+		snapshot, ok, err := GetSnapshot(channelID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil // Already restored
+		}
+
+		wait := time.Until(snapshot.Until)
+		if wait <= 0 {
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			fmt.Printf("Restoring the rewards early.\n")
+			return Restore(c, channelID)
+		}
+	}
+
+	// The token may have expired during a long wait.
+	if refreshed, err := twitch.GetClient(); err == nil {
+		c = refreshed
+	}
+
+	if err := Restore(c, channelID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Ad over; rewards restored.\n")
+	return nil
+}
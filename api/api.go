@@ -1,10 +1,23 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/monktype/msc/adpause"
+	"github.com/monktype/msc/lockdown"
+	"github.com/monktype/msc/pollscript"
+	"github.com/monktype/msc/rewardstats"
+	"github.com/monktype/msc/shoutouts"
+	"github.com/monktype/msc/timers"
 	"github.com/monktype/msc/twitch"
 	"github.com/nicklaw5/helix/v2"
 )
@@ -18,7 +31,12 @@ func ApiServer(port int) error {
 	r.POST("/createpoll", createPollHandler)
 	r.GET("/getpolls", getPollsHandler) // All polls, not specific poll detail
 	r.GET("/getpoll", getPollHandler)   // Information about a single poll
+	r.GET("/polls/history", getPollHistoryHandler)
 	r.POST("/endpoll", endPollHandler)
+	r.POST("/createprediction", createPredictionHandler)
+	r.GET("/getpredictions", getPredictionsHandler) // Recent predictions, not specific prediction detail
+	r.GET("/getprediction", getPredictionHandler)   // Information about a single prediction
+	r.POST("/endprediction", endPredictionHandler)
 	r.POST("/startcommercial", startCommercialHandler)
 	r.POST("/sendannouncement", sendAnnouncementHandler)
 	r.POST("/sendshoutout", sendShoutoutHandler)
@@ -28,6 +46,46 @@ func ApiServer(port int) error {
 	r.POST("/slowmode", slowmodeHandler)
 	r.POST("/slowmodeduration", slowmodeDurationHandler)
 	r.POST("/submode", subOnlyModeHandler)
+	r.POST("/deletechatmessage", deleteChatMessageHandler)
+	r.POST("/clearchat", clearChatHandler)
+	r.GET("/blockedterms", getBlockedTermsHandler)
+	r.POST("/blockedterms", addBlockedTermHandler)
+	r.DELETE("/blockedterms", removeBlockedTermHandler)
+	r.POST("/blockedterms/import", importBlockedTermsHandler)
+	r.GET("/automod/settings", getAutoModSettingsHandler)
+	r.PUT("/automod/settings", updateAutoModSettingsHandler)
+	r.POST("/automod/check", checkAutoModHandler)
+	r.GET("/automod/queue", getAutoModQueueHandler)
+	r.POST("/automod/queue", resolveAutoModQueueHandler)
+	r.GET("/shieldmode", getShieldModeHandler)
+	r.POST("/shieldmode", shieldModeHandler)
+	r.GET("/timers", getTimersHandler)
+	r.POST("/timers/pause", pauseTimersHandler)
+	r.POST("/timers/resume", resumeTimersHandler)
+	r.GET("/polls/script", getPollScriptHandler)
+	r.POST("/polls/script/pause", pausePollScriptHandler)
+	r.POST("/polls/script/resume", resumePollScriptHandler)
+	r.POST("/polls/script/skip", skipPollScriptHandler)
+	r.POST("/warn", warnUserHandler)
+	r.GET("/unbanrequests", getUnbanRequestsHandler)
+	r.POST("/unbanrequests/resolve", resolveUnbanRequestHandler)
+	r.GET("/shoutouts", getShoutoutsHandler)
+	r.GET("/lockdown", getLockdownHandler)
+	r.POST("/lockdown", lockdownHandler)
+	r.POST("/raid", startRaidHandler)
+	r.POST("/raid/cancel", cancelRaidHandler)
+	r.GET("/rewards/stats", getRewardStatsHandler)
+	r.PATCH("/rewards/:id", updateRewardHandler)
+
+	// Queued shoutouts (from /sendshoutout or `msc shoutout --queue`) are sent for as long as the server runs.
+	go shoutouts.Work(make(chan struct{}),
+		func(entry shoutouts.Entry) {
+			fmt.Printf("Sent queued shoutout to %s\n", entry.Target())
+		},
+		func(entry shoutouts.Entry, err error, dropped bool) {
+			fmt.Printf("Queued shoutout to %s failed (dropped: %t): %s\n", entry.Target(), dropped, err)
+		},
+	)
 
 	listenAddr := fmt.Sprintf("localhost:%d", port)
 	if err := r.Run(listenAddr); err != nil {
@@ -51,6 +109,17 @@ func internalErrorHandler(c *gin.Context, err error) {
 	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 }
 
+// pollValidationErrorHandler answers with every violation when err is a *twitch.PollValidationError.
+func pollValidationErrorHandler(c *gin.Context, err error) {
+	validationErr, ok := err.(*twitch.PollValidationError)
+	if !ok {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "violations": validationErr.Violations})
+}
+
 // GET /userid/:username
 func getUserIdHandler(c *gin.Context) {
 	username := c.Query("username")
@@ -106,6 +175,11 @@ func createPollHandler(c *gin.Context) {
 		Title             string   `json:"title" binding:"required"`
 		DurationInSeconds int      `json:"duration" binding:"required"`
 		Options           []string `json:"options" binding:"required"`
+
+		ChannelPointsVotingEnabled bool `json:"channel_points_voting_enabled"`
+		ChannelPointsPerVote       int  `json:"channel_points_per_vote"`
+
+		Truncate bool `json:"truncate"` // Shorten the title and options to Twitch's limits instead of refusing them
 	}
 
 	if err := c.ShouldBindJSON(&pollRequest); err != nil {
@@ -113,13 +187,32 @@ func createPollHandler(c *gin.Context) {
 		return
 	}
 
+	channelPointsPerVote := 0
+	if pollRequest.ChannelPointsVotingEnabled {
+		if pollRequest.ChannelPointsPerVote < 1 {
+			errorHandler(c, fmt.Errorf("channel_points_per_vote must be at least 1 when channel_points_voting_enabled is true"))
+			return
+		}
+		channelPointsPerVote = pollRequest.ChannelPointsPerVote
+	}
+
+	title, options := pollRequest.Title, pollRequest.Options
+	if pollRequest.Truncate {
+		title, options, _ = twitch.TruncatePoll(title, options)
+	}
+
+	if err := twitch.ValidatePoll(title, pollRequest.DurationInSeconds, options, channelPointsPerVote); err != nil {
+		pollValidationErrorHandler(c, err)
+		return
+	}
+
 	client, err := twitch.GetClient()
 	if err != nil {
 		internalErrorHandler(c, err)
 		return
 	}
 
-	pollID, err := twitch.CreatePoll(client, pollRequest.ChannelID, pollRequest.Title, pollRequest.DurationInSeconds, pollRequest.Options)
+	pollID, err := twitch.CreatePoll(client, pollRequest.ChannelID, title, pollRequest.DurationInSeconds, options, channelPointsPerVote)
 	if err != nil {
 		errorHandler(c, err)
 		return
@@ -155,6 +248,63 @@ func getPollsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, polls)
 }
 
+// GET /polls/history/:channel_id/:since/:format
+// since defaults to 7d; format is json (the default), csv, or markdown.
+func getPollHistoryHandler(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if !slices.Contains(twitch.PollHistoryFormats, format) {
+		errorHandler(c, fmt.Errorf("format must be one of %s", strings.Join(twitch.PollHistoryFormats, ", ")))
+		return
+	}
+
+	since, err := twitch.ParseSince(c.DefaultQuery("since", "7d"))
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	polls, err := twitch.GetPollHistory(client, channelID, since)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	summaries := make([]twitch.PollSummary, 0, len(polls))
+	for _, poll := range polls {
+		summaries = append(summaries, twitch.SummarizePoll(poll))
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, summaries)
+		return
+	}
+
+	var out strings.Builder
+	err = twitch.WritePollHistory(&out, summaries, format)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == "markdown" {
+		contentType = "text/markdown; charset=utf-8"
+	}
+	c.Data(http.StatusOK, contentType, []byte(out.String()))
+}
+
 // GET /getpoll/:channel_id/:poll_id
 func getPollHandler(c *gin.Context) {
 	channelID := c.Query("channel_id")
@@ -189,6 +339,7 @@ func endPollHandler(c *gin.Context) {
 	var endPollRequest struct {
 		ChannelID string `json:"channel_id" binding:"required"`
 		PollID    string `json:"poll_id" binding:"required"`
+		Archive   bool   `json:"archive"` // Also hide the results from the channel
 	}
 
 	if err := c.ShouldBindJSON(&endPollRequest); err != nil {
@@ -202,7 +353,12 @@ func endPollHandler(c *gin.Context) {
 		return
 	}
 
-	if err := twitch.EndPoll(client, endPollRequest.ChannelID, endPollRequest.PollID); err != nil {
+	endPoll := twitch.EndPoll
+	if endPollRequest.Archive {
+		endPoll = twitch.ArchivePoll
+	}
+
+	if err := endPoll(client, endPollRequest.ChannelID, endPollRequest.PollID); err != nil {
 		errorHandler(c, err)
 		return
 	}
@@ -210,6 +366,121 @@ func endPollHandler(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil) // No Content response
 }
 
+// POST /createprediction
+func createPredictionHandler(c *gin.Context) {
+	var predictionRequest struct {
+		ChannelID         string   `json:"channel_id" binding:"required"`
+		Title             string   `json:"title" binding:"required,max=45"`
+		DurationInSeconds int      `json:"duration" binding:"required,min=30,max=1800"`
+		Outcomes          []string `json:"outcomes" binding:"required,min=2,max=10,dive,max=25"`
+	}
+
+	if err := c.ShouldBindJSON(&predictionRequest); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	predictionID, err := twitch.CreatePrediction(client, predictionRequest.ChannelID, predictionRequest.Title, predictionRequest.DurationInSeconds, predictionRequest.Outcomes)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	response := struct {
+		PredictionID string `json:"prediction_id"`
+	}{PredictionID: predictionID}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /getpredictions/:channel_id
+func getPredictionsHandler(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	predictions, err := twitch.GetPredictions(client, channelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, predictions)
+}
+
+// GET /getprediction/:channel_id/:prediction_id
+func getPredictionHandler(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	predictionID := c.Query("prediction_id")
+	if predictionID == "" {
+		errorHandler(c, fmt.Errorf("prediction_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	prediction, err := twitch.GetPrediction(client, channelID, predictionID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prediction)
+}
+
+// POST /endprediction
+// Status LOCKED closes it to new predictions, RESOLVED pays out winning_outcome_id, and CANCELED refunds everyone.
+func endPredictionHandler(c *gin.Context) {
+	var endPredictionRequest struct {
+		ChannelID        string `json:"channel_id" binding:"required"`
+		PredictionID     string `json:"prediction_id" binding:"required"`
+		Status           string `json:"status" binding:"required,oneof=LOCKED RESOLVED CANCELED"`
+		WinningOutcomeID string `json:"winning_outcome_id" binding:"required_if=Status RESOLVED"`
+	}
+
+	if err := c.ShouldBindJSON(&endPredictionRequest); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	prediction, err := twitch.EndPrediction(client, endPredictionRequest.ChannelID, endPredictionRequest.PredictionID, endPredictionRequest.Status, endPredictionRequest.WinningOutcomeID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prediction)
+}
+
 // POST /reward
 func createRewardHandler(c *gin.Context) {
 	type CreateRewardParams struct {
@@ -275,6 +546,90 @@ func createRewardHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GET /rewards/stats/:channel_id/:since/:until/:top/:format
+// Reads redemptions recorded by msc reward stats record or backfill. since defaults to 7d and until to now ("all"
+// leaves either open); top defaults to 10; format is json (the default) or csv.
+func getRewardStatsHandler(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		errorHandler(c, fmt.Errorf("format must be json or csv"))
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top < 0 {
+		errorHandler(c, fmt.Errorf("top must be a number, 0 or more"))
+		return
+	}
+
+	since, until, err := rewardstats.ParseWindow(c.DefaultQuery("since", "7d"), c.Query("until"))
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	redemptions, err := rewardstats.Load(channelID)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	stats := rewardstats.Compute(redemptions, since, until, top)
+
+	if format == "json" {
+		c.JSON(http.StatusOK, stats)
+		return
+	}
+
+	var out strings.Builder
+	err = rewardstats.WriteStats(&out, stats, format)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(out.String()))
+}
+
+// PATCH /rewards/:id
+// Only the fields given are changed; they're named like Twitch's, so is_paused, cost, and so on.
+func updateRewardHandler(c *gin.Context) {
+	var request struct {
+		ChannelID string `json:"channel_id" binding:"required"`
+		twitch.RewardUpdate
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	if request.RewardUpdate.IsEmpty() {
+		errorHandler(c, fmt.Errorf("give at least one field to update"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	reward, err := twitch.UpdateReward(client, request.ChannelID, c.Param("id"), request.RewardUpdate)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reward)
+}
+
 // DELETE /reward/:channelID/:rewardID
 func deleteRewardHandler(c *gin.Context) {
 	channelID := c.Query("channel_id")
@@ -435,6 +790,8 @@ func startCommercialHandler(c *gin.Context) {
 	type StartCommercialParams struct {
 		ChannelID string `json:"channel_id" binding:"required"`
 		Length    int    `json:"length" binding:"required" binding:"min=30,max=180"` // Enforce valid lengths
+
+		NoPauseRewards bool `json:"no_pause_rewards"` // Don't pause the ad-pause rewards for this ad
 	}
 
 	var params StartCommercialParams
@@ -475,6 +832,15 @@ func startCommercialHandler(c *gin.Context) {
 		return
 	}
 
+	// Pause the rewards set with msc reward ad-pause for the ad, and put them back after it.
+	if !params.NoPauseRewards {
+		go func() {
+			if err := adpause.Run(client, params.ChannelID, time.Duration(params.Length)*time.Second, nil); err != nil {
+				fmt.Printf("Ad pause for channel %s: %s\n", params.ChannelID, err)
+			}
+		}()
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Commercial started successfully"})
 }
 
@@ -484,7 +850,8 @@ func sendAnnouncementHandler(c *gin.Context) {
 		UserID    string `json:"user_id" binding:"required"`
 		ChannelID string `json:"channel_id" binding:"required"`
 		Color     string `json:"color" binding:"required,oneof=primary blue green orange purple"` // Validate against allowed colors
-		Message   string `json:"message" binding:"required"`
+		Message   string `json:"message" binding:"required"`                                      // Template variables are filled in; long or multi-line messages become several announcements
+		DelayMS   *int   `json:"delay_ms"`                                                        // Delay between split announcements
 	}
 
 	var params SendAnnouncementParams
@@ -517,21 +884,54 @@ func sendAnnouncementHandler(c *gin.Context) {
 		return
 	}
 
-	err = twitch.SendAnnouncement(client, params.UserID, params.ChannelID, colorEnum, params.Message)
+	delay := twitch.DefaultAnnouncementDelay
+	if params.DelayMS != nil {
+		delay = time.Duration(*params.DelayMS) * time.Millisecond
+	}
+
+	announcements, err := twitch.RenderAnnouncements(client, params.ChannelID, params.Message, nil)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+	if len(announcements) == 0 {
+		errorHandler(c, fmt.Errorf("message is empty"))
+		return
+	}
+
+	// Send the first part now so a bad request still fails here.
+	err = twitch.SendAnnouncement(client, params.UserID, params.ChannelID, colorEnum, announcements[0])
 	if err != nil {
 		errorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Announcement sent successfully"})
+	if len(announcements) == 1 {
+		c.JSON(http.StatusOK, gin.H{"message": "Announcement sent successfully", "parts": 1, "announcements": announcements})
+		return
+	}
+
+	// The rest wait delay apart, which could hold the request open for a long time, so they're sent in the background.
+	go func() {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		err := twitch.SendAnnouncements(client, params.UserID, params.ChannelID, colorEnum, announcements[1:], delay)
+		if err != nil {
+			fmt.Printf("Failed to send the rest of a %d part announcement: %s\n", len(announcements), err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "First announcement sent; the rest are being sent", "parts": len(announcements), "announcements": announcements})
 }
 
 // POST /sendshoutout
 func sendShoutoutHandler(c *gin.Context) {
 	var request struct {
-		UserID    string `json:"user_id" binding:"required"`
-		ChannelID string `json:"channel_id" binding:"required"`
-		TargetID  string `json:"target_id" binding:"required"`
+		UserID     string `json:"user_id" binding:"required"`
+		ChannelID  string `json:"channel_id" binding:"required"`
+		TargetID   string `json:"target_id" binding:"required"`
+		TargetName string `json:"target_name"` // Optional, for showing in the queue
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -539,19 +939,35 @@ func sendShoutoutHandler(c *gin.Context) {
 		return
 	}
 
-	client, err := twitch.GetClient()
+	// Shoutouts go through the queue so Twitch's cooldowns don't refuse them.
+	result, err := shoutouts.Enqueue(request.UserID, request.ChannelID, request.TargetID, request.TargetName)
 	if err != nil {
 		internalErrorHandler(c, err)
 		return
 	}
 
-	err = twitch.SendShoutout(client, request.UserID, request.ChannelID, request.TargetID)
+	if result.Skipped != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Shoutout not queued: %s", result.Skipped)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shoutout queued successfully", "position": result.Position, "eta": result.Entry.ETA})
+}
+
+// GET /shoutouts/:channel_id
+// The shoutout queue in send order, for one channel or (without channel_id) every channel.
+func getShoutoutsHandler(c *gin.Context) {
+	entries, err := shoutouts.Pending(c.Query("channel_id"))
 	if err != nil {
-		errorHandler(c, err)
+		internalErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Shoutout sent successfully"})
+	if entries == nil {
+		entries = []shoutouts.Entry{}
+	}
+
+	c.JSON(http.StatusOK, entries)
 }
 
 // POST /emoteonly
@@ -721,3 +1137,858 @@ func subOnlyModeHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Subscriber only mode set successfully"})
 }
+
+// POST /deletechatmessage
+func deleteChatMessageHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+		MessageID string `json:"message_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	err = twitch.DeleteChatMessage(client, request.UserID, request.ChannelID, request.MessageID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chat message deleted successfully"})
+}
+
+// POST /clearchat
+func clearChatHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	err = twitch.ClearChat(client, request.UserID, request.ChannelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chat cleared successfully"})
+}
+
+// GET /blockedterms/:user_id/:channel_id
+func getBlockedTermsHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	terms, err := twitch.GetBlockedTerms(client, userID, channelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// POST /blockedterms
+func addBlockedTermHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+		Text      string `json:"text" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	term, err := twitch.AddBlockedTerm(client, request.UserID, request.ChannelID, request.Text)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// DELETE /blockedterms/:user_id/:channel_id/:term_id
+func removeBlockedTermHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	termID := c.Query("term_id")
+	if termID == "" {
+		errorHandler(c, fmt.Errorf("term_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if err := twitch.RemoveBlockedTerm(client, userID, channelID, termID); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// POST /blockedterms/import
+// Adds the given terms that aren't already blocked; with "sync" it also removes blocked terms not in the list.
+func importBlockedTermsHandler(c *gin.Context) {
+	var request struct {
+		UserID    string   `json:"user_id" binding:"required"`
+		ChannelID string   `json:"channel_id" binding:"required"`
+		Terms     []string `json:"terms" binding:"required,min=1"`
+		Sync      bool     `json:"sync"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	added, removed, err := twitch.SyncBlockedTerms(client, request.UserID, request.ChannelID, request.Terms, request.Sync)
+	if err != nil {
+		// Some terms may have been changed before the failure, so include what happened.
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "added": added, "removed": removed})
+		return
+	}
+
+	response := struct {
+		Added   []string `json:"added"`
+		Removed []string `json:"removed"`
+	}{Added: added, Removed: removed}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /automod/settings/:user_id/:channel_id
+func getAutoModSettingsHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	settings, err := twitch.GetAutoModSettings(client, userID, channelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// PUT /automod/settings
+// Either overall_level or levels (per category, keyed like the GET response) must be given, not both.
+func updateAutoModSettingsHandler(c *gin.Context) {
+	var request struct {
+		UserID       string         `json:"user_id" binding:"required"`
+		ChannelID    string         `json:"channel_id" binding:"required"`
+		OverallLevel *int           `json:"overall_level"`
+		Levels       map[string]int `json:"levels"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	if (request.OverallLevel == nil) == (len(request.Levels) == 0) {
+		errorHandler(c, fmt.Errorf("give either overall_level or levels"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	var settings twitch.AutoModSettings
+	if request.OverallLevel != nil {
+		settings, err = twitch.SetAutoModOverallLevel(client, request.UserID, request.ChannelID, *request.OverallLevel)
+	} else {
+		settings, err = twitch.SetAutoModCategoryLevels(client, request.UserID, request.ChannelID, request.Levels)
+	}
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// POST /automod/check
+func checkAutoModHandler(c *gin.Context) {
+	var request struct {
+		ChannelID string `json:"channel_id" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	permitted, err := twitch.CheckAutoModStatus(client, request.ChannelID, request.Message)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"is_permitted": permitted})
+}
+
+// The API server keeps one held message queue per channel, started by the first GET /automod/queue for it.
+// Starting one connects to EventSub, so it happens outside the lock: the channel's slot is reserved first, requests for
+// the same channel wait on it, and requests for other channels carry on.
+var (
+	autoModQueues     = make(map[string]*autoModQueueSlot)
+	autoModQueuesLock sync.Mutex
+)
+
+// autoModQueueSlot is a channel's queue; queue and err are set before ready is closed.
+type autoModQueueSlot struct {
+	ready chan struct{}
+	queue *twitch.AutoModQueue
+	err   error
+}
+
+// getAutoModQueue returns the running queue for the channel, starting one if there isn't one (or the old one stopped).
+func getAutoModQueue(userID string, channelID string) (*twitch.AutoModQueue, error) {
+	autoModQueuesLock.Lock()
+	slot, ok := autoModQueues[channelID]
+	if ok {
+		select {
+		case <-slot.ready:
+			select {
+			case <-slot.queue.Done():
+				ok = false // Stopped; start over below.
+			default:
+			}
+		default:
+			// Another request is starting it; wait for that below.
+		}
+	}
+
+	if ok {
+		autoModQueuesLock.Unlock()
+		<-slot.ready
+		return slot.queue, slot.err
+	}
+
+	slot = &autoModQueueSlot{ready: make(chan struct{})}
+	autoModQueues[channelID] = slot
+	autoModQueuesLock.Unlock()
+
+	client, err := twitch.GetClient()
+	if err == nil {
+		slot.queue, err = twitch.StartAutoModQueue(client, userID, channelID, nil, nil)
+	}
+	slot.err = err
+
+	if err != nil {
+		// Free the slot so the next request tries again.
+		autoModQueuesLock.Lock()
+		if autoModQueues[channelID] == slot {
+			delete(autoModQueues, channelID)
+		}
+		autoModQueuesLock.Unlock()
+	}
+	close(slot.ready)
+
+	return slot.queue, slot.err
+}
+
+// GET /automod/queue/:user_id/:channel_id
+// The first call for a channel starts listening, so it returns an empty list.
+func getAutoModQueueHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	queue, err := getAutoModQueue(userID, channelID)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, queue.Messages())
+}
+
+// POST /automod/queue
+func resolveAutoModQueueHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+		MessageID string `json:"message_id" binding:"required"`
+		Action    string `json:"action" binding:"required,oneof=allow deny ALLOW DENY"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	queue, err := getAutoModQueue(request.UserID, request.ChannelID)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	allow := strings.EqualFold(request.Action, "allow")
+	if err := queue.Resolve(client, request.UserID, request.MessageID, allow); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	if allow {
+		c.JSON(http.StatusOK, gin.H{"message": "Held message allowed successfully"})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "Held message denied successfully"})
+	}
+}
+
+// GET /shieldmode/:user_id/:channel_id
+func getShieldModeHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	status, err := twitch.GetShieldModeStatus(client, userID, channelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /shieldmode
+// Leaving out "state" flips Shield Mode from whatever it is now, for one-button toggles.
+func shieldModeHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+		State     *bool  `json:"state"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	var state bool
+	if request.State != nil {
+		state = *request.State
+	} else {
+		current, err := twitch.GetShieldModeStatus(client, request.UserID, request.ChannelID)
+		if err != nil {
+			errorHandler(c, err)
+			return
+		}
+		state = !current.IsActive
+	}
+
+	status, err := twitch.UpdateShieldModeStatus(client, request.UserID, request.ChannelID, state)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /warn
+func warnUserHandler(c *gin.Context) {
+	var request struct {
+		UserID    string `json:"user_id" binding:"required"`
+		ChannelID string `json:"channel_id" binding:"required"`
+		TargetID  string `json:"target_id" binding:"required"`
+		Reason    string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	err = twitch.WarnUser(client, request.UserID, request.ChannelID, request.TargetID, request.Reason)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User warned successfully"})
+}
+
+// GET /unbanrequests/:user_id/:channel_id/:status
+func getUnbanRequestsHandler(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		errorHandler(c, fmt.Errorf("user_id parameter is required"))
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	status := c.DefaultQuery("status", "pending")
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	requests, err := twitch.GetUnbanRequests(client, userID, channelID, status)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// POST /unbanrequests/resolve
+func resolveUnbanRequestHandler(c *gin.Context) {
+	var request struct {
+		UserID         string `json:"user_id" binding:"required"`
+		ChannelID      string `json:"channel_id" binding:"required"`
+		UnbanRequestID string `json:"unban_request_id" binding:"required"`
+		Status         string `json:"status" binding:"required,oneof=approved denied"`
+		ResolutionText string `json:"resolution_text"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	resolved, err := twitch.ResolveUnbanRequest(client, request.UserID, request.ChannelID, request.UnbanRequestID, request.Status == "approved", request.ResolutionText)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resolved)
+}
+
+// GET /timers
+// What the running (or last) `msc timers run` reported, plus the pause state.
+func getTimersHandler(c *gin.Context) {
+	status, err := timers.GetStatus()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	paused, err := timers.GetPauseState()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status, "paused": paused})
+}
+
+type timerNameParams struct {
+	Name string `json:"name"` // Empty means every timer
+}
+
+// bindTimerName reads the timer name; an empty body is the same as {}, every timer.
+func bindTimerName(c *gin.Context) (timerNameParams, error) {
+	var params timerNameParams
+	if err := c.ShouldBindJSON(&params); err != nil && !errors.Is(err, io.EOF) {
+		return params, err
+	}
+	return params, nil
+}
+
+// timerErrorHandler answers 400 for a timer name that isn't one of the runner's timers, and 500 otherwise.
+func timerErrorHandler(c *gin.Context, err error) {
+	if _, ok := err.(*timers.UnknownTimerError); ok {
+		errorHandler(c, err)
+		return
+	}
+	internalErrorHandler(c, err)
+}
+
+// POST /timers/pause
+func pauseTimersHandler(c *gin.Context) {
+	params, err := bindTimerName(c)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	if err := timers.Pause(params.Name); err != nil {
+		timerErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Timers paused successfully"})
+}
+
+// POST /timers/resume
+func resumeTimersHandler(c *gin.Context) {
+	params, err := bindTimerName(c)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	if err := timers.Resume(params.Name); err != nil {
+		timerErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Timers resumed successfully"})
+}
+
+// GET /polls/script
+// What the running (or last) `msc poll run-script` reported, with its scoreboard, plus the pause and skip state.
+func getPollScriptHandler(c *gin.Context) {
+	status, err := pollscript.GetStatus()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	control, err := pollscript.GetControl()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status, "control": control})
+}
+
+// POST /polls/script/pause
+func pausePollScriptHandler(c *gin.Context) {
+	if err := pollscript.Pause(); err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Poll script paused successfully"})
+}
+
+// POST /polls/script/resume
+func resumePollScriptHandler(c *gin.Context) {
+	if err := pollscript.Resume(); err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Poll script resumed successfully"})
+}
+
+// POST /polls/script/skip
+// Ends the running poll without scoring it, or skips the next poll if none is running.
+func skipPollScriptHandler(c *gin.Context) {
+	if err := pollscript.Skip(); err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Poll script skip requested successfully"})
+}
+
+// POST /raid
+// Checks that the target is live (unless force), sends the announcement if there is one, and starts the raid.
+// The announcement can use {target}, {target_game}, {target_title}, {target_viewers}, and the announcement variables.
+func startRaidHandler(c *gin.Context) {
+	var request struct {
+		UserID       string `json:"user_id" binding:"required"`
+		ChannelID    string `json:"channel_id" binding:"required"`
+		TargetID     string `json:"target_id" binding:"required"`
+		Announcement string `json:"announcement"`
+		Color        string `json:"color" binding:"omitempty,oneof=primary blue green orange purple"`
+		Force        bool   `json:"force"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	stream, live, err := twitch.GetStream(client, request.TargetID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+	if !live && !request.Force {
+		errorHandler(c, fmt.Errorf("raid target is offline; set force to raid anyway"))
+		return
+	}
+
+	if request.Announcement != "" {
+		color := twitch.AnnouncementColorPrimary
+		for colorEnum, name := range twitch.AnnouncementColorMap {
+			if name == request.Color {
+				color = colorEnum
+			}
+		}
+
+		values := map[string]string{
+			"target":         stream.UserName,
+			"target_game":    stream.GameName,
+			"target_title":   stream.Title,
+			"target_viewers": fmt.Sprintf("%d", stream.ViewerCount),
+		}
+		if !live {
+			info, err := twitch.GetChannelInformation(client, request.TargetID)
+			if err != nil {
+				errorHandler(c, err)
+				return
+			}
+			values["target"] = info.BroadcasterName
+			values["target_game"] = info.GameName
+			values["target_title"] = info.Title
+		}
+
+		announcements, err := twitch.RenderAnnouncements(client, request.ChannelID, request.Announcement, values)
+		if err == nil {
+			err = twitch.SendAnnouncements(client, request.UserID, request.ChannelID, color, announcements, twitch.DefaultAnnouncementDelay)
+		}
+		if err != nil {
+			// This isn't a fatal thing, the raid still goes ahead.
+			fmt.Printf("Failed to send raid announcement: %s\n", err)
+		}
+	}
+
+	err = twitch.StartRaid(client, request.ChannelID, request.TargetID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Raid started successfully", "live": live, "target": stream})
+}
+
+// POST /raid/cancel
+func cancelRaidHandler(c *gin.Context) {
+	var request struct {
+		ChannelID string `json:"channel_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	err = twitch.CancelRaid(client, request.ChannelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Raid canceled successfully"})
+}
+
+// GET /lockdown/:channel_id
+// Whether the channel is locked down, and the settings that lifting it restores.
+func getLockdownHandler(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	if channelID == "" {
+		errorHandler(c, fmt.Errorf("channel_id parameter is required"))
+		return
+	}
+
+	snapshot, locked, err := lockdown.GetSnapshot(channelID)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"locked": locked, "snapshot": snapshot})
+}
+
+// POST /lockdown
+// State true saves the chat settings and applies the profile (or the default profile); false restores them.
+func lockdownHandler(c *gin.Context) {
+	var request struct {
+		UserID    string            `json:"user_id" binding:"required"`
+		ChannelID string            `json:"channel_id" binding:"required"`
+		State     bool              `json:"state"`
+		Profile   *lockdown.Profile `json:"profile"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	client, err := twitch.GetClient()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if request.State {
+		profile := lockdown.DefaultProfile()
+		if request.Profile != nil {
+			profile = *request.Profile
+		}
+		if err := profile.Validate(); err != nil {
+			errorHandler(c, err)
+			return
+		}
+
+		snapshot, err := lockdown.On(client, request.UserID, request.ChannelID, profile)
+		if err != nil {
+			errorHandler(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Lockdown on", "snapshot": snapshot})
+		return
+	}
+
+	snapshot, err := lockdown.Off(client, request.UserID, request.ChannelID)
+	if err != nil {
+		errorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lockdown off", "snapshot": snapshot})
+}
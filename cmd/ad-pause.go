@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/monktype/msc/adpause"
+	"github.com/monktype/msc/eventsub"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+// How often watch checks the ad schedule when EventSub isn't available.
+const adScheduleInterval = 15 * time.Second
+
+var rewardsadpauseCmd = &cobra.Command{
+	Use:   "ad-pause",
+	Short: "Pause a set of rewards while ads run, then put them back how they were",
+}
+
+var rewardsadpauseAddCmd = &cobra.Command{
+	Use:   "add <reward>...",
+	Short: "Pause these rewards (IDs or titles) during ads on -c (channel name)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, _, err := adPauseChannel(cmd)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := adpause.AddRewards(channelID, args)
+		if err != nil {
+			return err
+		}
+
+		printAdPauseRewards(rewards)
+		return nil
+	},
+}
+
+var rewardsadpauseRemoveCmd = &cobra.Command{
+	Use:   "remove <reward>...",
+	Short: "Stop pausing these rewards (IDs or titles) during ads on -c (channel name)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, _, err := adPauseChannel(cmd)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := adpause.RemoveRewards(channelID, args)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		printAdPauseRewards(rewards)
+		return nil
+	},
+}
+
+var rewardsadpauseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the rewards paused during ads on -c (channel name), and any ad pause going on",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, _, err := adPauseChannel(cmd)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := adpause.Rewards(channelID)
+		if err != nil {
+			return err
+		}
+		printAdPauseRewards(rewards)
+
+		snapshot, ok, err := adpause.GetSnapshot(channelID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Printf("\nPaused for an ad until %s:\n", snapshot.Until.Local().Format("15:04:05"))
+			for id, reward := range snapshot.Rewards {
+				fmt.Printf("  %s (%s), was paused before: %s\n", reward.Title, id, onOff(reward.WasPaused))
+			}
+		}
+
+		return nil
+	},
+}
+
+var rewardsadpauseRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Put rewards paused for an ad on -c (channel name) back how they were now, without waiting for the ad to end",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, c, err := adPauseChannel(cmd)
+		if err != nil {
+			return err
+		}
+
+		if _, ok, err := adpause.GetSnapshot(channelID); err != nil || !ok {
+			if err == nil {
+				fmt.Printf("No rewards are paused for an ad.\n")
+			}
+			return err
+		}
+
+		if err := adpause.Restore(c, channelID); err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		fmt.Printf("Rewards restored.\n")
+		return nil
+	},
+}
+
+var rewardsadpauseWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Pause the rewards for every ad on -c (channel name), automatic ones included, until CTRL+C",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, c, err := adPauseChannel(cmd)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := adpause.Rewards(channelID)
+		if err != nil {
+			return err
+		}
+		if len(rewards) == 0 {
+			fmt.Printf("No rewards are set to pause during ads; add some with msc reward ad-pause add.\n")
+			return fmt.Errorf("no rewards to pause")
+		}
+
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		fmt.Printf("Pausing %d rewards during ads. Press CTRL+C to stop.\n\n", len(rewards))
+
+		// Pauses that are waiting out an ad restore early on CTRL+C; wait for them so nothing is left paused.
+		var running sync.WaitGroup
+		defer running.Wait()
+
+		onAd := func(length time.Duration, automatic bool) {
+			kind := "An ad"
+			if automatic {
+				kind = "An automatic ad"
+			}
+			fmt.Printf("%s started (%s).\n", kind, length)
+
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				// Get a fresh client in case the watcher has been going long enough for the token to need a refresh.
+				client := c
+				if refreshed, err := twitch.GetClient(); err == nil {
+					client = refreshed
+				}
+				if err := adpause.Run(client, channelID, length, stop); err != nil {
+					fmt.Printf("%s\n", err)
+				}
+			}()
+		}
+
+		if err := watchAdBreakEvents(c, channelID, onAd, stop); err != nil {
+			fmt.Printf("EventSub isn't available (%s); checking the ad schedule every %s instead.\n", err, adScheduleInterval)
+			watchAdSchedule(c, channelID, onAd, stop)
+		}
+
+		return nil
+	},
+}
+
+// adPauseChannel reads -c and looks up the channel.
+// Returns the channel ID, the client, and error.
+func adPauseChannel(cmd *cobra.Command) (string, helix.Client, error) {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return "", helix.Client{}, err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return "", helix.Client{}, err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return "", helix.Client{}, err
+	}
+
+	return channelID, c, nil
+}
+
+func printAdPauseRewards(rewards []string) {
+	if len(rewards) == 0 {
+		fmt.Printf("No rewards are paused during ads.\n")
+		return
+	}
+
+	fmt.Printf("Rewards paused during ads:\n")
+	for _, reward := range rewards {
+		fmt.Printf("  %s\n", reward)
+	}
+}
+
+// watchAdBreakEvents calls onAd for each channel.ad_break.begin event until stop is closed.
+// Returns an error if the EventSub subscription can't be made or the connection is lost.
+func watchAdBreakEvents(c helix.Client, channelID string, onAd func(time.Duration, bool), stop <-chan struct{}) error {
+	session, err := eventsub.Connect()
+	if err != nil {
+		return err
+	}
+
+	if err := twitch.SubscribeAdBreakEvents(c, session.ID, channelID); err != nil {
+		session.Close()
+		return err
+	}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- session.Listen(func(n eventsub.Notification) {
+			if n.Type != "channel.ad_break.begin" {
+				return
+			}
+
+			var event twitch.AdBreakEvent
+			if err := json.Unmarshal(n.Event, &event); err != nil {
+				fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+				return
+			}
+
+			onAd(time.Duration(event.DurationSeconds)*time.Second, event.IsAutomatic)
+		})
+	}()
+
+	select {
+	case <-stop:
+		session.Close()
+		<-listenErr
+	case err := <-listenErr:
+		return err
+	}
+
+	return nil
+}
+
+// watchAdSchedule checks the ad schedule until stop is closed and calls onAd when the last ad time moves on, with
+// what's left of the ad. Ads are caught up to adScheduleInterval late.
+func watchAdSchedule(c helix.Client, channelID string, onAd func(time.Duration, bool), stop <-chan struct{}) {
+	var lastAd time.Time
+
+	for {
+		if refreshed, err := twitch.GetClient(); err == nil {
+			c = refreshed
+		}
+
+		if schedule, err := twitch.GetAdSchedule(c, channelID); err == nil && schedule.LastAdAt.After(lastAd) {
+			lastAd = schedule.LastAdAt.Time
+			// The schedule only has the next ad's length; it's the best guess there is for the one that just started.
+			remaining := time.Until(lastAd.Add(time.Duration(schedule.Duration) * time.Second))
+			if remaining > 0 {
+				onAd(remaining.Round(time.Second), false)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(adScheduleInterval):
+		}
+	}
+}
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/monktype/msc/adpause"
 	"github.com/monktype/msc/twitch"
 	"github.com/nicklaw5/helix/v2"
 	"github.com/spf13/cobra"
@@ -47,11 +52,35 @@ var startadCmd = &cobra.Command{
 			return fmt.Errorf("use correct length value")
 		}
 
-		err = twitch.StartCommercial(c, channelname, lengthEnum)
+		channelid, err := twitch.GetUserID(c, channelname)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		err = twitch.StartCommercial(c, channelid, lengthEnum)
+		if err != nil {
+			return err
+		}
+
+		nopause, err := cmd.Flags().GetBool("no-pause-rewards")
+		if err != nil {
+			return err
+		}
+
+		rewards, err := adpause.Rewards(channelid)
+		if err != nil || nopause || len(rewards) == 0 {
+			return err
+		}
+
+		// Wait out the ad to put the rewards back; CTRL+C puts them back right away.
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		return adpause.Run(c, channelid, time.Duration(length)*time.Second, stop)
 	},
 }
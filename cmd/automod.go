@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var automodCmd = &cobra.Command{
+	Use:   "automod",
+	Short: "Show and tune AutoMod, check messages against it, and review held messages",
+}
+
+var automodSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Show or set AutoMod settings with -c (channel name) flag",
+}
+
+var automodSettingsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show AutoMod settings with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		settings, err := twitch.GetAutoModSettings(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		printAutoModSettings(settings)
+		return nil
+	},
+}
+
+var automodSettingsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set AutoMod with -c (channel name) and either -o (overall level) or per-category level flags (0..4)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		overallSet := cmd.Flags().Changed("overall")
+		overall, err := cmd.Flags().GetInt("overall")
+		if err != nil {
+			return err
+		}
+
+		levels := make(map[string]int)
+		for _, category := range twitch.AutoModCategories {
+			flagName := strings.ReplaceAll(category, "_", "-")
+			if !cmd.Flags().Changed(flagName) {
+				continue
+			}
+			level, err := cmd.Flags().GetInt(flagName)
+			if err != nil {
+				return err
+			}
+			levels[category] = level
+		}
+
+		if overallSet && len(levels) != 0 {
+			fmt.Printf("Use either --overall or the per-category flags, not both.\n")
+			return fmt.Errorf("conflicting AutoMod flags")
+		}
+		if !overallSet && len(levels) == 0 {
+			fmt.Printf("Nothing to set; give --overall or at least one per-category flag.\n")
+			return fmt.Errorf("no AutoMod levels given")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		var settings twitch.AutoModSettings
+		if overallSet {
+			settings, err = twitch.SetAutoModOverallLevel(c, userID, channelID, overall)
+		} else {
+			settings, err = twitch.SetAutoModCategoryLevels(c, userID, channelID, levels)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("AutoMod settings updated.\n\n")
+		printAutoModSettings(settings)
+		return nil
+	},
+}
+
+var automodCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether AutoMod would allow a message with -c (channel name), followed by the message (broadcaster only)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			fmt.Printf("At least 1 word is required.\n")
+			return fmt.Errorf("give a message to check")
+		}
+
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		permitted, err := twitch.CheckAutoModStatus(c, channelID, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+
+		if permitted {
+			fmt.Printf("AutoMod would allow this message.\n")
+		} else {
+			fmt.Printf("AutoMod would hold this message.\n")
+		}
+
+		return nil
+	},
+}
+
+var automodQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Review messages AutoMod holds on -c (channel name) as they arrive; approve or deny them interactively",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		queue, err := twitch.StartAutoModQueue(c, userID, channelID,
+			func(held twitch.HeldMessage) {
+				fmt.Printf("\n[HELD %s] %s (%s, level %d): %s\n", held.MessageID, held.UserName, held.Category, held.Level, held.Text)
+			},
+			func(messageID string, status string) {
+				fmt.Printf("\n[%s] %s was resolved elsewhere.\n", strings.ToUpper(status), messageID)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer queue.Stop()
+
+		fmt.Printf("Listening for held messages on %s.\n", channelname)
+		fmt.Printf("Commands: \"l\" lists held messages, \"a <n>\" approves, \"d <n>\" denies (n from the list, or a message ID), \"q\" quits.\n\n")
+
+		lines := make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			close(lines)
+		}()
+
+		for {
+			select {
+			case <-queue.Done():
+				if queue.Err() != nil {
+					fmt.Printf("Stopped receiving held messages: %s\n", queue.Err())
+				}
+				return queue.Err()
+			case line, ok := <-lines:
+				if !ok {
+					return nil
+				}
+
+				fields := strings.Fields(line)
+				if len(fields) == 0 {
+					continue
+				}
+
+				switch strings.ToLower(fields[0]) {
+				case "q", "quit":
+					return nil
+				case "l", "list":
+					messages := queue.Messages()
+					if len(messages) == 0 {
+						fmt.Printf("No held messages.\n")
+					}
+					for i, held := range messages {
+						fmt.Printf("%d) %s: %s (%s, level %d, held %s)\n", i+1, held.UserName, held.Text, held.Category, held.Level, held.HeldAt.Local().Format("15:04:05"))
+					}
+				case "a", "approve", "d", "deny":
+					if len(fields) < 2 {
+						fmt.Printf("Give a number from the list or a message ID.\n")
+						continue
+					}
+
+					messageID := fields[1]
+					if n, err := strconv.Atoi(fields[1]); err == nil {
+						messages := queue.Messages()
+						if n < 1 || n > len(messages) {
+							fmt.Printf("There's no held message %d; use \"l\" to list them.\n", n)
+							continue
+						}
+						messageID = messages[n-1].MessageID
+					}
+
+					allow := strings.HasPrefix(strings.ToLower(fields[0]), "a")
+
+					// Get a fresh client in case the session has been open long enough for the token to need a refresh.
+					c, err := twitch.GetClient()
+					if err != nil {
+						fmt.Printf("Failed to get client: %s\n", err)
+						continue
+					}
+
+					if err := queue.Resolve(c, userID, messageID, allow); err != nil {
+						continue // Already printed
+					}
+					if allow {
+						fmt.Printf("Approved %s.\n", messageID)
+					} else {
+						fmt.Printf("Denied %s.\n", messageID)
+					}
+				default:
+					fmt.Printf("Unknown command %s.\n", fields[0])
+				}
+			}
+		}
+	},
+}
+
+func printAutoModSettings(settings twitch.AutoModSettings) {
+	if settings.OverallLevel != nil {
+		fmt.Printf("Overall level: %d\n", *settings.OverallLevel)
+	} else {
+		fmt.Printf("Overall level: (set per category)\n")
+	}
+
+	levels := settings.CategoryLevels()
+	for _, category := range twitch.AutoModCategories {
+		fmt.Printf("  %s: %d\n", category, levels[category])
+	}
+}
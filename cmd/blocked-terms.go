@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+var blockedTermsCmd = &cobra.Command{
+	Use:   "blocked-terms",
+	Short: "List, add, remove, import, or export blocked terms with -c (channel name) flag",
+}
+
+var blockedTermsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all blocked terms on the channel",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		terms, err := twitch.GetBlockedTerms(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		if len(terms) != 0 {
+			fmt.Printf("Blocked terms on channel (%d):\n\n", len(terms))
+
+			for _, term := range terms {
+				fmt.Printf("%s:\t%s\n", term.ID, term.Text)
+			}
+
+			fmt.Printf("\n")
+		} else {
+			fmt.Printf("No blocked terms currently found.\n")
+		}
+
+		return nil
+	},
+}
+
+var blockedTermsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add blocked terms; each argument is one term (quote phrases)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			fmt.Printf("At least 1 term is required.\n")
+			return fmt.Errorf("give at least one term")
+		}
+
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		for _, text := range args {
+			term, err := twitch.AddBlockedTerm(c, userID, channelID, text)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Added blocked term \"%s\" with ID %s\n", term.Text, term.ID)
+		}
+
+		return nil
+	},
+}
+
+var blockedTermsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove blocked terms; each argument is a term's text or its ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			fmt.Printf("At least 1 term is required.\n")
+			return fmt.Errorf("give at least one term")
+		}
+
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		terms, err := twitch.GetBlockedTerms(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range args {
+			var found *helix.BlockedTerm
+			for i := range terms {
+				if terms[i].ID == arg || strings.EqualFold(terms[i].Text, arg) {
+					found = &terms[i]
+					break
+				}
+			}
+
+			if found == nil {
+				fmt.Printf("Blocked term \"%s\" was not found on the channel, skipping.\n", arg)
+				continue
+			}
+
+			err := twitch.RemoveBlockedTerm(c, userID, channelID, found.ID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed blocked term \"%s\" (%s)\n", found.Text, found.ID)
+		}
+
+		return nil
+	},
+}
+
+var blockedTermsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Add the blocked terms from a file (one per line, or CSV) that aren't on the channel yet",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		sync, err := cmd.Flags().GetBool("sync")
+		if err != nil {
+			return err
+		}
+
+		dryrun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		fileTerms, err := readBlockedTermsFile(args[0])
+		if err != nil {
+			fmt.Printf("Failed to read %s: %s\n", args[0], err)
+			return err
+		}
+
+		if sync {
+			if err := twitch.CheckBlockedTermsSync(fileTerms); err != nil {
+				fmt.Printf("%s has no terms in it; %s.\n", args[0], err)
+				return err
+			}
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		current, err := twitch.GetBlockedTerms(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		add, remove := twitch.PlanBlockedTerms(current, fileTerms, sync)
+		for _, term := range add {
+			fmt.Printf("+ %s\n", term)
+		}
+		for _, term := range remove {
+			fmt.Printf("- %s\n", term.Text)
+		}
+
+		if len(add) == 0 && len(remove) == 0 {
+			fmt.Printf("Blocked terms already match %s.\n", args[0])
+			return nil
+		}
+
+		if dryrun {
+			fmt.Printf("\nDry run; nothing was changed.\n")
+			return nil
+		}
+
+		// Removing terms can't be taken back easily, so ask first; only adding terms goes ahead.
+		if len(remove) != 0 && !yes && !askConfirmation(fmt.Sprintf("\nAdd %d and remove %d blocked terms?", len(add), len(remove))) {
+			fmt.Printf("Not changing blocked terms.\n")
+			return nil
+		}
+
+		added, removed, err := twitch.ApplyBlockedTerms(c, userID, channelID, add, remove)
+		if err != nil {
+			fmt.Printf("Stopped after adding %d and removing %d blocked terms.\n", len(added), len(removed))
+			return err
+		}
+
+		fmt.Printf("Added %d and removed %d blocked terms.\n", len(added), len(removed))
+		return nil
+	},
+}
+
+var blockedTermsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the channel's blocked terms to a file (or stdout if no file is given)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		if format == "" {
+			format = "text"
+			if len(args) == 1 && strings.EqualFold(filepath.Ext(args[0]), ".csv") {
+				format = "csv"
+			}
+		}
+		if format != "text" && format != "csv" {
+			fmt.Printf("Format can only be text or csv.\n")
+			return fmt.Errorf("format string is incorrect")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		terms, err := twitch.GetBlockedTerms(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		out := os.Stdout
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				fmt.Printf("Failed to create %s: %s\n", args[0], err)
+				return err
+			}
+			defer out.Close()
+		}
+
+		err = writeBlockedTerms(out, terms, format)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("Exported %d blocked terms to %s\n", len(terms), args[0])
+		}
+
+		return nil
+	},
+}
+
+// readBlockedTermsFile reads terms from a file.
+// Files ending in .csv use the "text" column if there's a header with one, otherwise the first column.
+// Anything else is read as one term per line; blank lines and lines starting with # are skipped.
+func readBlockedTermsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var terms []string
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1 // Rows don't have to match the header's width
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		column := 0
+		if len(records) != 0 {
+			for i, name := range records[0] {
+				if strings.EqualFold(strings.TrimSpace(name), "text") {
+					column = i
+					records = records[1:] // Drop the header row
+					break
+				}
+			}
+		}
+
+		for _, record := range records {
+			if column < len(record) && strings.TrimSpace(record[column]) != "" {
+				terms = append(terms, strings.TrimSpace(record[column]))
+			}
+		}
+
+		return terms, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+
+	return terms, scanner.Err()
+}
+
+// writeBlockedTerms writes terms as "text" (one per line) or "csv" (with a header, text first so it imports back).
+func writeBlockedTerms(w io.Writer, terms []helix.BlockedTerm, format string) error {
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"text", "id", "created_at", "expires_at"})
+		for _, term := range terms {
+			expires := ""
+			if !term.ExpiresAt.IsZero() {
+				expires = term.ExpiresAt.Format(time.RFC3339)
+			}
+			writer.Write([]string{term.Text, term.ID, term.CreatedAt.Format(time.RFC3339), expires})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	for _, term := range terms {
+		if _, err := fmt.Fprintln(w, term.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -174,32 +174,81 @@ var rewardsredemptionsCmd = &cobra.Command{
 			return err
 		}
 
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+
+		sortraw, err := cmd.Flags().GetString("sort")
+		if err != nil {
+			return err
+		}
+
 		status := strings.ToUpper(statusraw) // The Twitch API wants it upper-cased.
 		if status != "CANCELED" && status != "FULFILLED" && status != "UNFULFILLED" {
 			fmt.Printf("Status can only be CANCELED or FULFILLED or UNFULFILLED.\n")
 			return fmt.Errorf("status string is incorrect")
 		}
 
+		sort := strings.ToUpper(sortraw)
+		if sort != "OLDEST" && sort != "NEWEST" {
+			fmt.Printf("Sort can only be OLDEST or NEWEST.\n")
+			return fmt.Errorf("sort string is incorrect")
+		}
+
+		if rewardid == "" && !all {
+			fmt.Printf("Give a reward with -r, or use --all to list every reward's redemptions.\n")
+			return fmt.Errorf("missing reward")
+		}
+
 		channelid, err := twitch.GetUserID(c, channelname)
 		if err != nil {
 			return err
 		}
 
-		redemptions, err := twitch.GetRedemptions(c, channelid, rewardid, status)
-		if err != nil {
-			return err
+		// Without --all, only the first page comes back, like the Twitch dashboard shows.
+		limit := 50
+		if all {
+			limit = 0
 		}
 
-		if len(redemptions) != 0 {
-			fmt.Printf("Current redemptions on channel:\n\n")
+		rewards := []helix.ChannelCustomReward{{ID: rewardid}}
+		if all {
+			rewards, err = redemptionRewards(c, channelid, rewardid)
+			if err != nil {
+				return err
+			}
+		}
+
+		found := 0
+		for _, reward := range rewards {
+			redemptions, err := twitch.ListRedemptions(c, channelid, reward.ID, status, sort, limit)
+			if err != nil {
+				return err
+			}
+			if len(redemptions) == 0 {
+				continue
+			}
+
+			if found == 0 {
+				fmt.Printf("Current redemptions on channel:\n\n")
+			}
+			if reward.Title != "" {
+				fmt.Printf("%s (%s):\n", reward.Title, reward.ID)
+			}
 
 			for _, redemption := range redemptions {
 				fmt.Printf("%s by user %s (%s) (%s)\n", redemption.ID, redemption.UserName, redemption.UserID, redemption.Status)
 			}
 
 			fmt.Printf("\n")
-		} else {
+			found += len(redemptions)
+		}
+
+		if found == 0 {
 			fmt.Printf("No rewards currently found.\n")
+		} else if all {
+			fmt.Printf("%d redemptions.\n", found)
 		}
 
 		return nil
@@ -208,8 +257,16 @@ var rewardsredemptionsCmd = &cobra.Command{
 
 var rewardscancelCmd = &cobra.Command{
 	Use:   "cancel",
-	Short: "Cancel a Channel Point Reward Redemption",
+	Short: "Cancel a Channel Point Reward Redemption, or every matching one with --all",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		if all {
+			return bulkUpdateRedemptions(cmd, "CANCELED")
+		}
+
 		c, err := twitch.GetClient()
 		if err != nil {
 			return err
@@ -229,6 +286,10 @@ var rewardscancelCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if rewardid == "" || redemptionid == "" {
+			fmt.Printf("Give the reward with -r and the redemption with -i, or use --all.\n")
+			return fmt.Errorf("missing reward or redemption")
+		}
 
 		channelid, err := twitch.GetUserID(c, channelname)
 		if err != nil {
@@ -256,8 +317,16 @@ var rewardscancelCmd = &cobra.Command{
 
 var rewardsfulfillCmd = &cobra.Command{
 	Use:   "fulfill",
-	Short: "Fulfill a Channel Point Reward Redemption",
+	Short: "Fulfill a Channel Point Reward Redemption, or every matching one with --all",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		if all {
+			return bulkUpdateRedemptions(cmd, "FULFILLED")
+		}
+
 		c, err := twitch.GetClient()
 		if err != nil {
 			return err
@@ -277,6 +346,10 @@ var rewardsfulfillCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if rewardid == "" || redemptionid == "" {
+			fmt.Printf("Give the reward with -r and the redemption with -i, or use --all.\n")
+			return fmt.Errorf("missing reward or redemption")
+		}
 
 		channelid, err := twitch.GetUserID(c, channelname)
 		if err != nil {
@@ -301,3 +374,149 @@ var rewardsfulfillCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var rewardsupdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a Channel Point Reward in place, keeping its ID and pending redemptions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		rewardid, err := cmd.Flags().GetString("reward")
+		if err != nil {
+			return err
+		}
+
+		update, err := rewardUpdateFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if update.IsEmpty() {
+			fmt.Printf("Give at least one thing to change; see --help.\n")
+			return fmt.Errorf("nothing to update")
+		}
+
+		channelid, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		reward, err := twitch.UpdateReward(c, channelid, rewardid, update)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated reward %s:\n\n", reward.ID)
+		printRewardDetails(reward)
+
+		return nil
+	},
+}
+
+// rewardUpdateFromFlags builds a reward update from only the flags that were given.
+// Limits and the cooldown are turned off with 0.
+func rewardUpdateFromFlags(cmd *cobra.Command) (twitch.RewardUpdate, error) {
+	var update twitch.RewardUpdate
+	flags := cmd.Flags()
+
+	stringFlags := map[string]**string{
+		"title":            &update.Title,
+		"user-prompt":      &update.Prompt,
+		"background-color": &update.BackgroundColor,
+	}
+	for name, field := range stringFlags {
+		if flags.Changed(name) {
+			value, err := flags.GetString(name)
+			if err != nil {
+				return update, err
+			}
+			*field = &value
+		}
+	}
+
+	boolFlags := map[string]**bool{
+		"enabled":        &update.IsEnabled,
+		"paused":         &update.IsPaused,
+		"input-required": &update.IsUserInputRequired,
+		"skip-queue":     &update.ShouldRedemptionsSkipRequestQueue,
+	}
+	for name, field := range boolFlags {
+		if flags.Changed(name) {
+			value, err := flags.GetBool(name)
+			if err != nil {
+				return update, err
+			}
+			*field = &value
+		}
+	}
+
+	if flags.Changed("points") {
+		cost, err := flags.GetInt("points")
+		if err != nil {
+			return update, err
+		}
+		if cost < 1 {
+			return update, fmt.Errorf("the cost must be at least 1 point")
+		}
+		update.Cost = &cost
+	}
+
+	// Each limit is a number plus an on/off switch on Twitch's side; 0 here means off.
+	limitFlags := []struct {
+		name    string
+		enabled **bool
+		value   **int
+	}{
+		{"max-per-stream", &update.IsMaxPerStreamEnabled, &update.MaxPerStream},
+		{"max-per-user-per-stream", &update.IsMaxPerUserPerStreamEnabled, &update.MaxPerUserPerStream},
+		{"global-cooldown", &update.IsGlobalCooldownEnabled, &update.GlobalCooldownSeconds},
+	}
+	for _, limit := range limitFlags {
+		if !flags.Changed(limit.name) {
+			continue
+		}
+		value, err := flags.GetInt(limit.name)
+		if err != nil {
+			return update, err
+		}
+		if value < 0 {
+			return update, fmt.Errorf("--%s can't be negative", limit.name)
+		}
+
+		enabled := value > 0
+		*limit.enabled = &enabled
+		if enabled {
+			*limit.value = &value
+		}
+	}
+
+	return update, nil
+}
+
+// printRewardDetails prints a reward's settings.
+func printRewardDetails(reward helix.ChannelCustomReward) {
+	fmt.Printf("Title: %s\n", reward.Title)
+	fmt.Printf("Cost: %d\n", reward.Cost)
+	if reward.Prompt != "" {
+		fmt.Printf("Prompt: %s\n", reward.Prompt)
+	}
+	fmt.Printf("Background color: %s\n", reward.BackgroundColor)
+	fmt.Printf("Enabled: %t, paused: %t, input required: %t, skips request queue: %t\n", reward.IsEnabled, reward.IsPaused, reward.IsUserInputRequired, reward.ShouldRedemptionsSkipRequestQueue)
+
+	if reward.MaxPerStreamSetting.IsEnabled {
+		fmt.Printf("Max per stream: %d\n", reward.MaxPerStreamSetting.MaxPerStream)
+	}
+	if reward.MaxPerUserPerStreamSetting.IsEnabled {
+		fmt.Printf("Max per user per stream: %d\n", reward.MaxPerUserPerStreamSetting.MaxPerUserPerStream)
+	}
+	if reward.GlobalCooldownSetting.IsEnabled {
+		fmt.Printf("Global cooldown: %d seconds\n", reward.GlobalCooldownSetting.GlobalCooldownSeconds)
+	}
+}
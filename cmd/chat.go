@@ -1,22 +1,31 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/monktype/msc/shoutouts"
 	"github.com/monktype/msc/twitch"
 	"github.com/spf13/cobra"
 )
 
 var announcementCmd = &cobra.Command{
 	Use:   "announcement",
-	Short: "Create an announcement with -c (channel name), -b (border-color), followed by announcement message",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
-			fmt.Printf("At least 1 word is required.\n")
-			return fmt.Errorf("")
-		}
+	Short: "Create an announcement with -c (channel name), -b (border-color), followed by announcement message (or -f file, or stdin)",
+	Long: `Create an announcement with -c (channel name), -b (border-color), followed by announcement message.
 
+The message can instead come from a file with -f (each non-blank line is sent as its own announcement; "-f -" reads stdin),
+or is read from stdin when no message is given and stdin isn't a terminal.
+
+These variables are filled in from the channel: {channel}, {game}, {title}, {uptime}, {viewer_count}, {date}.
+Messages over 500 characters are split into several announcements, sent -d (delay) apart.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		channelname, err := cmd.Flags().GetString("channel-name")
 		if err != nil {
 			return err
@@ -27,6 +36,26 @@ var announcementCmd = &cobra.Command{
 			return err
 		}
 
+		filename, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+
+		delay, err := cmd.Flags().GetDuration("delay")
+		if err != nil {
+			return err
+		}
+
+		message, err := readAnnouncementText(args, filename)
+		if err != nil {
+			return err
+		}
+
+		selectedColor, err := parseAnnouncementColor(userColor)
+		if err != nil {
+			return err
+		}
+
 		c, err := twitch.GetClient()
 		if err != nil {
 			return err
@@ -42,34 +71,78 @@ var announcementCmd = &cobra.Command{
 			return err
 		}
 
-		var selectedColor twitch.AnnouncementColor
-		foundColor := false
-
-		for color, name := range twitch.AnnouncementColorMap {
-			if strings.EqualFold(userColor, name) {
-				selectedColor = color
-				foundColor = true
-				break
-			}
+		announcements, err := twitch.RenderAnnouncements(c, channelID, message, nil)
+		if err != nil {
+			return err
 		}
-
-		if !foundColor {
-			fmt.Printf("Color %s is invalid; please select \"primary\", \"blue\", \"green\", \"orange\", or \"purple\"", userColor)
-			return fmt.Errorf("please select a valid color or let it default to \"primary\"")
+		if len(announcements) == 0 {
+			fmt.Printf("The announcement is empty.\n")
+			return fmt.Errorf("nothing to announce")
 		}
 
-		err = twitch.SendAnnouncement(c, userID, channelID, selectedColor, strings.Join(args, " "))
+		err = twitch.SendAnnouncements(c, userID, channelID, selectedColor, announcements, delay)
 		if err != nil {
 			return err
 		}
 
+		if len(announcements) > 1 {
+			fmt.Printf("Sent %d announcements.\n", len(announcements))
+		}
+
 		return nil
 	},
 }
 
+// readAnnouncementText gets the announcement text from the arguments, a file ("-" is stdin), or piped stdin, in that order.
+func readAnnouncementText(args []string, filename string) (string, error) {
+	if len(args) > 0 && filename != "" {
+		fmt.Printf("Give the announcement as arguments or with --file, not both.\n")
+		return "", fmt.Errorf("conflicting announcement input")
+	}
+
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+
+	if filename == "" {
+		stat, err := os.Stdin.Stat()
+		if err != nil || stat.Mode()&os.ModeCharDevice != 0 {
+			fmt.Printf("At least 1 word is required.\n")
+			return "", fmt.Errorf("")
+		}
+		filename = "-"
+	}
+
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		fmt.Printf("Failed to read announcement: %s\n", err)
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// parseAnnouncementColor matches a color name from the command line to a twitch.AnnouncementColor.
+func parseAnnouncementColor(userColor string) (twitch.AnnouncementColor, error) {
+	for color, name := range twitch.AnnouncementColorMap {
+		if strings.EqualFold(userColor, name) {
+			return color, nil
+		}
+	}
+
+	fmt.Printf("Color %s is invalid; please select \"primary\", \"blue\", \"green\", \"orange\", or \"purple\"", userColor)
+	return twitch.AnnouncementColorPrimary, fmt.Errorf("please select a valid color or let it default to \"primary\"")
+}
+
 var shoutoutCmd = &cobra.Command{
 	Use:   "shoutout",
-	Short: "Create a shoutout with -c (channel name), -s (shoutout name)",
+	Short: "Create a shoutout with -c (channel name), -s (shoutout name); with -q (queue), queue each argument and send them as cooldowns allow",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		shoutoutname, err := cmd.Flags().GetString("shoutout-name")
 		if err != nil {
@@ -81,17 +154,27 @@ var shoutoutCmd = &cobra.Command{
 			return err
 		}
 
-		c, err := twitch.GetClient()
+		useQueue, err := cmd.Flags().GetBool("queue")
 		if err != nil {
 			return err
 		}
 
-		channelID, err := twitch.GetUserID(c, channelname)
+		nowait, err := cmd.Flags().GetBool("no-wait")
 		if err != nil {
 			return err
 		}
 
-		targetID, err := twitch.GetUserID(c, shoutoutname)
+		if !useQueue && (shoutoutname == "" || len(args) != 0) {
+			fmt.Printf("Give one channel with -s, or use --queue for several.\n")
+			return fmt.Errorf("give the right number of arguments")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
 		if err != nil {
 			return err
 		}
@@ -101,15 +184,122 @@ var shoutoutCmd = &cobra.Command{
 			return err
 		}
 
-		err = twitch.SendShoutout(c, userID, channelID, targetID)
+		if !useQueue {
+			targetID, err := twitch.GetUserID(c, shoutoutname)
+			if err != nil {
+				return err
+			}
+
+			err = twitch.SendShoutout(c, userID, channelID, targetID)
+			if err != nil {
+				return err
+			}
+
+			// Queued shoutouts have to wait out the cooldowns this one just started.
+			if err := shoutouts.RecordSent(channelID, targetID, time.Now()); err != nil {
+				fmt.Printf("The shoutout was sent, but saving it for the queue's cooldowns failed: %s\n", err)
+			}
+			return nil
+		}
+
+		targets := args
+		if shoutoutname != "" {
+			targets = append([]string{shoutoutname}, targets...)
+		}
+
+		for _, target := range targets {
+			targetID, err := twitch.GetUserID(c, target)
+			if err != nil {
+				return err
+			}
+
+			result, err := shoutouts.Enqueue(userID, channelID, targetID, target)
+			if err != nil {
+				return err
+			}
+
+			if result.Skipped != "" {
+				fmt.Printf("Skipped %s: %s\n", target, result.Skipped)
+			} else {
+				fmt.Printf("Queued %s at position %d (ETA %s)\n", target, result.Position, result.Entry.ETA.Local().Format("15:04:05"))
+			}
+		}
+
+		pending, err := shoutouts.Pending(channelID)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		if len(pending) == 0 {
+			fmt.Printf("The shoutout queue is empty.\n")
+			return nil
+		}
+
+		fmt.Printf("\nShoutout queue for %s (%d):\n", channelname, len(pending))
+		for i, entry := range pending {
+			fmt.Printf("%d) %s at %s\n", i+1, entry.Target(), entry.ETA.Local().Format("15:04:05"))
+		}
+
+		if nowait {
+			fmt.Printf("\nNot waiting; the queue is sent by `msc api` or the next `msc shoutout --queue` on this channel.\n")
+			return nil
+		}
+
+		return workShoutoutQueue(channelID)
 	},
 }
 
+// workShoutoutQueue sends queued shoutouts as their cooldowns pass, until the channel's queue is empty or CTRL+C.
+func workShoutoutQueue(channelID string) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	fmt.Printf("\nWaiting to send queued shoutouts. Press CTRL+C to stop; the rest stay queued.\n")
+
+	for {
+		pending, err := shoutouts.Pending(channelID)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Printf("All queued shoutouts sent.\n")
+			return nil
+		}
+
+		if wait := time.Until(pending[0].ETA); wait > 0 {
+			select {
+			case <-signals:
+				fmt.Printf("Stopped with %d shoutouts still queued.\n", len(pending))
+				return nil
+			case <-time.After(wait):
+			}
+		}
+
+		// Get a fresh client in case the queue has been going long enough for the token to need a refresh.
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		err = shoutouts.SendDue(c,
+			func(entry shoutouts.Entry) {
+				fmt.Printf("%s Sent shoutout to %s\n", time.Now().Format("15:04:05"), entry.Target())
+			},
+			func(entry shoutouts.Entry, err error, dropped bool) {
+				if dropped {
+					fmt.Printf("%s Shoutout to %s failed too many times; dropped it from the queue.\n", time.Now().Format("15:04:05"), entry.Target())
+				} else {
+					fmt.Printf("%s Shoutout to %s failed; it'll be tried again after the cooldown.\n", time.Now().Format("15:04:05"), entry.Target())
+				}
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+}
+
 var emoteonlyCmd = &cobra.Command{
 	Use:   "emote-only",
 	Short: "Enable or Disable emote-only mode with -c (channel name) flag",
@@ -479,3 +669,105 @@ var submodeOffCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Delete chat messages or clear chat with -c (channel name) flag",
+}
+
+var chatDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a single chat message with -c (channel name) and -m (message ID) flags",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		messageID, err := cmd.Flags().GetString("message-id")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		err = twitch.DeleteChatMessage(c, userID, channelID, messageID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted message %s.\n", messageID)
+		return nil
+	},
+}
+
+var chatClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear all chat messages with -c (channel name) flag; asks for confirmation unless --yes is given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		if !yes && !askConfirmation(fmt.Sprintf("Clear ALL chat messages on %s?", channelname)) {
+			fmt.Printf("Not clearing chat.\n")
+			return nil
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		err = twitch.ClearChat(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Chat cleared on %s.\n", channelname)
+		return nil
+	},
+}
+
+// askConfirmation asks a yes/no question on stdin and returns true only for "y" or "yes".
+func askConfirmation(question string) bool {
+	fmt.Printf("%s [y/N] -> ", question)
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Printf("\nFailed to read your input: %s\n", err)
+		return false
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/monktype/msc/lockdown"
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var lockdownCmd = &cobra.Command{
+	Use:   "lockdown",
+	Short: "Lock chat down (and put it back) with -c (channel name) flag",
+}
+
+var lockdownOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Save the current chat settings and lock chat down with -c (channel name) and optionally -p (profile file)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		profilePath, err := cmd.Flags().GetString("profile")
+		if err != nil {
+			return err
+		}
+
+		profile := lockdown.DefaultProfile()
+		if profilePath != "" {
+			profile, err = lockdown.LoadProfile(profilePath)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return err
+			}
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := lockdown.On(c, userID, channelID, profile)
+		if err != nil {
+			if snapshot.ChannelID != "" {
+				fmt.Printf("Lockdown was only partly applied; `msc lockdown off` still restores the saved settings.\n")
+			}
+			return err
+		}
+
+		fmt.Printf("Chat is locked down. Settings from %s are saved; `msc lockdown off` restores them.\n", snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+var lockdownOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Restore the chat settings saved by `lockdown on` with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := lockdown.Off(c, userID, channelID)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		fmt.Printf("Lockdown lifted; chat settings are back to how they were at %s.\n", snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+var lockdownStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether chat is locked down and the saved settings with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		snapshot, locked, err := lockdown.GetSnapshot(channelID)
+		if err != nil {
+			return err
+		}
+
+		if !locked {
+			fmt.Printf("Chat isn't locked down.\n")
+			return nil
+		}
+
+		fmt.Printf("Chat is locked down since %s. `lockdown off` restores:\n", snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Shield Mode: %s\n", onOff(snapshot.ShieldMode))
+		fmt.Printf("  Emote-only: %s\n", onOff(snapshot.EmoteMode))
+		fmt.Printf("  Subscribers-only: %s\n", onOff(snapshot.SubscriberMode))
+		if snapshot.FollowerMode {
+			fmt.Printf("  Followers-only: on (%d minutes)\n", snapshot.FollowerModeDuration)
+		} else {
+			fmt.Printf("  Followers-only: off\n")
+		}
+		if snapshot.SlowMode {
+			fmt.Printf("  Slow mode: on (%d seconds)\n", snapshot.SlowModeWaitTime)
+		} else {
+			fmt.Printf("  Slow mode: off\n")
+		}
+
+		return nil
+	},
+}
+
+func onOff(state bool) string {
+	if state {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var warnCmd = &cobra.Command{
+	Use:   "warn",
+	Short: "Warn a user with -c (channel name), -u (username), -r (reason)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		username, err := cmd.Flags().GetString("user")
+		if err != nil {
+			return err
+		}
+
+		reason, err := cmd.Flags().GetString("reason")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		targetID, err := twitch.GetUserID(c, username)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		err = twitch.WarnUser(c, userID, channelID, targetID, reason)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Warned %s.\n", username)
+		return nil
+	},
+}
+
+var unbanRequestsCmd = &cobra.Command{
+	Use:   "unban-requests",
+	Short: "List, approve, or deny unban requests with -c (channel name) flag",
+}
+
+var unbanRequestsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List unban requests with -c (channel name) and -s (status) flags",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		statusraw, err := cmd.Flags().GetString("status")
+		if err != nil {
+			return err
+		}
+
+		status := strings.ToLower(statusraw) // The Twitch API wants it lower-cased.
+		validStatus := false
+		for _, s := range twitch.UnbanRequestStatuses {
+			if status == s {
+				validStatus = true
+				break
+			}
+		}
+		if !validStatus {
+			fmt.Printf("Status can only be %s.\n", strings.Join(twitch.UnbanRequestStatuses, ", "))
+			return fmt.Errorf("status string is incorrect")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		requests, err := twitch.GetUnbanRequests(c, userID, channelID, status)
+		if err != nil {
+			return err
+		}
+
+		if len(requests) != 0 {
+			fmt.Printf("Unban requests on channel (%s, %d):\n\n", status, len(requests))
+
+			for _, request := range requests {
+				fmt.Printf("%s by user %s (%s) at %s: %s\n", request.ID, request.UserName, request.UserID, request.CreatedAt.Local().Format("2006-01-02 15:04"), request.Text)
+				if request.ResolutionText != "" {
+					fmt.Printf("\tResolved by %s: %s\n", request.ModeratorName, request.ResolutionText)
+				}
+			}
+
+			fmt.Printf("\n")
+		} else {
+			fmt.Printf("No %s unban requests currently found.\n", status)
+		}
+
+		return nil
+	},
+}
+
+var unbanRequestsApproveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve (unban) an unban request with -c (channel name), -i (request ID), and optionally -r (resolution text)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return resolveUnbanRequest(cmd, true)
+	},
+}
+
+var unbanRequestsDenyCmd = &cobra.Command{
+	Use:   "deny",
+	Short: "Deny an unban request with -c (channel name), -i (request ID), and optionally -r (resolution text)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return resolveUnbanRequest(cmd, false)
+	},
+}
+
+func resolveUnbanRequest(cmd *cobra.Command, approve bool) error {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return err
+	}
+
+	requestID, err := cmd.Flags().GetString("request")
+	if err != nil {
+		return err
+	}
+
+	resolution, err := cmd.Flags().GetString("resolution")
+	if err != nil {
+		return err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return err
+	}
+
+	userID, err := twitch.GetMyUserID(c)
+	if err != nil {
+		return err
+	}
+
+	request, err := twitch.ResolveUnbanRequest(c, userID, channelID, requestID, approve, resolution)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Unban request from %s is now %s.\n", request.UserName, request.Status)
+	return nil
+}
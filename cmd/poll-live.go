@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monktype/msc/eventsub"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+const pollChartWidth = 30 // Characters in a full bar
+
+// watchPollEvents watches a poll through EventSub poll progress and end events, redrawing a live chart as votes come in.
+// It only returns nil once the result has been sent on resultChan; any error means the caller should fall back to
+// checking the poll with GetPoll.
+func watchPollEvents(c helix.Client, channelID string, pollID string, resultChan chan<- WatchPollResult) error {
+	session, err := eventsub.Connect()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	err = twitch.SubscribePollEvents(c, session.ID, channelID)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	events := make(chan eventsub.Notification)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- session.Listen(func(n eventsub.Notification) {
+			select {
+			case events <- n:
+			case <-done:
+			}
+		})
+	}()
+
+	// Votes cast before the subscriptions started only show up here, and the poll may even have ended already.
+	poll, err := twitch.GetPoll(c, channelID, pollID)
+	if err != nil {
+		return err
+	}
+	if poll.ID == "" {
+		return fmt.Errorf("poll %s not found", pollID)
+	}
+	if poll.Status != "ACTIVE" {
+		sendPollResult(poll, resultChan)
+		return nil
+	}
+
+	endsAt := poll.StartedAt.Add(time.Duration(poll.Duration) * time.Second)
+	chart := pollChart{terminal: isTerminal(os.Stdout)}
+	chart.draw(poll, endsAt)
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	lastCheck := time.Now()
+
+	for {
+		select {
+		case n := <-events:
+			var event twitch.PollEvent
+			if err := json.Unmarshal(n.Event, &event); err != nil {
+				fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+				continue
+			}
+			if event.ID != pollID {
+				continue // Another poll on the channel
+			}
+
+			if n.Type == "channel.poll.end" {
+				chart.draw(event.Poll(), event.EndedAt)
+				sendPollResult(event.Poll(), resultChan)
+				return nil
+			}
+
+			poll = event.Poll()
+			if !event.EndsAt.IsZero() {
+				endsAt = event.EndsAt
+			}
+			chart.draw(poll, endsAt)
+		case <-ticker.C:
+			chart.draw(poll, endsAt)
+
+			// The end event should arrive right as the poll ends; if it's well overdue, ask Twitch instead of waiting forever.
+			if time.Since(endsAt) > 10*time.Second && time.Since(lastCheck) > 5*time.Second {
+				lastCheck = time.Now()
+				latest, err := twitch.GetPoll(c, channelID, pollID)
+				if err == nil && latest.ID != "" && latest.Status != "ACTIVE" {
+					sendPollResult(latest, resultChan)
+					return nil
+				}
+			}
+		case err := <-listenErr:
+			if err == nil {
+				err = fmt.Errorf("EventSub session closed")
+			}
+			return err
+		}
+	}
+}
+
+// sendPollResult prints a finished poll's votes and sends its result string to the watcher.
+func sendPollResult(poll helix.Poll, resultChan chan<- WatchPollResult) {
+	fmt.Printf("Poll completed! Here are the results for \"%s\":\n", poll.Title)
+
+	printPollChoices(poll)
+	fmt.Printf("\n")
+
+	resultChan <- WatchPollResult{Poll: poll, Result: pollResultString(poll), Error: nil}
+}
+
+// pollChart draws a poll's votes as a bar chart.
+// In a terminal it redraws over itself; otherwise it prints a new chart only when the votes change.
+type pollChart struct {
+	terminal  bool
+	lines     int    // Lines drawn last time, to move back over in a terminal
+	lastVotes string // Vote lines drawn last time, to skip repeats outside a terminal
+}
+
+func (p *pollChart) draw(poll helix.Poll, endsAt time.Time) {
+	lines := pollChartLines(poll)
+	votes := strings.Join(lines, "\n")
+	if !p.terminal && votes == p.lastVotes {
+		return
+	}
+	p.lastVotes = votes
+
+	total := 0
+	for _, choice := range poll.Choices {
+		total += choice.Votes
+	}
+
+	header := fmt.Sprintf("\"%s\": %d votes", poll.Title, total)
+	if poll.Status == "ACTIVE" {
+		left := time.Until(endsAt).Round(time.Second)
+		if left > 0 {
+			header += fmt.Sprintf(", %d:%02d left", int(left.Minutes()), int(left.Seconds())%60)
+		} else {
+			header += ", ending..."
+		}
+	} else {
+		header += ", ended"
+	}
+	lines = append([]string{header}, lines...)
+
+	if p.terminal && p.lines > 0 {
+		fmt.Printf("\033[%dA", p.lines) // Back up to the top of the last chart
+	}
+	for _, line := range lines {
+		if p.terminal {
+			fmt.Printf("\033[2K") // Clear what was on the line
+		}
+		fmt.Printf("%s\n", line)
+	}
+	p.lines = len(lines)
+}
+
+// pollChartLines makes one bar per choice, sized against the total votes.
+func pollChartLines(poll helix.Poll) []string {
+	total := 0
+	width := 0
+	for _, choice := range poll.Choices {
+		total += choice.Votes
+		width = max(width, utf8.RuneCountInString(choice.Title))
+	}
+
+	var lines []string
+	for _, choice := range poll.Choices {
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(choice.Votes) / float64(total)
+		}
+		filled := int(percent/100*pollChartWidth + 0.5)
+
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(choice.Title))
+		line := fmt.Sprintf("  %s%s %s%s %3.0f%% (%d)", choice.Title, padding, strings.Repeat("█", filled), strings.Repeat("░", pollChartWidth-filled), percent, choice.Votes)
+		if poll.ChannelPointsVotingEnabled && choice.ChannelPointsVotes > 0 {
+			line += fmt.Sprintf(", %d with channel points", choice.ChannelPointsVotes)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
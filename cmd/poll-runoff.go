@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+const (
+	defaultPollRunoffAnnouncement   = "Tie between {tied}! Runoff poll for {duration} seconds: \"{poll_title}\""
+	defaultPollTieBreakAnnouncement = "Still tied between {tied} after {rounds} runoffs, so the winner was picked at random: {winner}"
+)
+
+// pollRunoff is how to follow up a tied poll.
+type pollRunoff struct {
+	Rounds               int // Most runoff polls to run before picking at random
+	Duration             int // Seconds for each runoff poll
+	ChannelPointsPerVote int
+	AnnouncementText     string // Template for announcing each runoff poll
+	TieBreakText         string // Template for announcing a random tie-break
+}
+
+// watchPollWithRunoffs watches a poll and, while it ends in a tie, runs a runoff poll with only the tied options.
+// A first poll that nobody voted in, or any poll that didn't run to completion (ended early or archived), ends the
+// chain instead. A tie left after the last runoff, or after a runoff nobody voted in, is broken at random. The whole
+// chain is printed at the end, and the final result is announced if announceResult is set (a random tie-break is
+// always announced).
+func watchPollWithRunoffs(c helix.Client, channelID string, pollID string, title string, duration int, runoff pollRunoff, announceResult bool, resultTemplate string) error {
+	myUserID, err := twitch.GetMyUserID(c)
+	if err != nil {
+		// This isn't a fatal thing, just mention it and skip the announcements.
+		fmt.Printf("Runoffs won't be announced because getting the current user failed, but continuing with the poll: %s\n", err)
+	}
+
+	var chain []helix.Poll
+	resultstring := ""
+	tieBroken := false
+
+	for {
+		fmt.Printf("Waiting for poll completion...\n")
+		poll, result, err := watchPollCompletion(c, channelID, pollID)
+		if err != nil {
+			printPollChain(chain, "")
+			return err
+		}
+		fmt.Printf("\n%s\n", result)
+
+		chain = append(chain, poll)
+		resultstring = result
+
+		maxVotes, winners := twitch.PollWinners(poll)
+		if len(winners) < 2 {
+			break
+		}
+		// Only a poll that ran its course gets a runoff; TERMINATED (CTRL+C or msc poll end), ARCHIVED, and INVALID
+		// polls were stopped on purpose or can't be trusted.
+		if poll.Status != "COMPLETED" {
+			fmt.Printf("The poll was %s, so there's no runoff.\n", strings.ToLower(poll.Status))
+			break
+		}
+		// Nobody voting in the first poll isn't a tie worth a runoff. Nobody voting in a runoff leaves the tie it was
+		// for, which goes to the tie-break.
+		if maxVotes == 0 && len(chain) == 1 {
+			fmt.Printf("Nobody voted, so there's no runoff.\n")
+			break
+		}
+		tied := strings.Join(winners, ", ")
+
+		if len(chain) > runoff.Rounds || maxVotes == 0 {
+			winner, err := twitch.BreakTie(winners)
+			if err != nil {
+				printPollChain(chain, "")
+				return err
+			}
+
+			resultstring = fmt.Sprintf("Still tied between %s, so the winner was picked at random: %s", tied, winner)
+			tieBroken = true
+			fmt.Printf("\n%s\n", resultstring)
+
+			if myUserID != "" {
+				err = sendPollAnnouncement(c, myUserID, channelID, runoff.TieBreakText, map[string]string{
+					"poll_title": title,
+					"tied":       tied,
+					"winner":     winner,
+					"rounds":     fmt.Sprintf("%d", len(chain)-1),
+				})
+				if err != nil {
+					// This isn't a fatal thing, just mention it.
+					fmt.Printf("Failed to announce the tie-break: %s\n", err)
+				}
+			}
+			break
+		}
+
+		// A chain can run for a while, so pick up a refreshed token if there is one.
+		if refreshed, err := twitch.GetClient(); err == nil {
+			c = refreshed
+		}
+
+		runoffTitle := fmt.Sprintf("Runoff: %s", title)
+		if len(chain) > 1 {
+			runoffTitle = fmt.Sprintf("Runoff %d: %s", len(chain), title)
+		}
+		runoffTitle, options, _ := twitch.TruncatePoll(runoffTitle, winners)
+
+		pollID, err = twitch.CreatePoll(c, channelID, runoffTitle, runoff.Duration, options, runoff.ChannelPointsPerVote)
+		if err != nil {
+			printPollChain(chain, "")
+			return err
+		}
+		fmt.Printf("\nTie between %s, started runoff %d of %d: \"%s\" for %d seconds.\n\n", tied, len(chain), runoff.Rounds, runoffTitle, runoff.Duration)
+
+		if myUserID != "" {
+			err = sendPollAnnouncement(c, myUserID, channelID, runoff.AnnouncementText, map[string]string{
+				"poll_title": runoffTitle,
+				"duration":   fmt.Sprintf("%d", runoff.Duration),
+				"tied":       tied,
+			})
+			if err != nil {
+				// This isn't a fatal thing, just mention it.
+				fmt.Printf("Failed to announce the runoff but continuing with the poll: %s\n", err)
+			}
+		}
+	}
+
+	printPollChain(chain, resultstring)
+
+	if announceResult && !tieBroken && myUserID != "" {
+		err = sendPollAnnouncement(c, myUserID, channelID, resultTemplate, map[string]string{
+			"poll_title": title,
+			"duration":   fmt.Sprintf("%d", duration),
+			"result":     resultstring,
+		})
+		if err != nil {
+			// This isn't a fatal thing, just mention it.
+			fmt.Printf("Failed to send announcement result: %s\n", err)
+		}
+	}
+
+	return nil
+}
+
+// printPollChain prints each poll of a runoff chain with its outcome, then the final result if there is one.
+func printPollChain(chain []helix.Poll, final string) {
+	if len(chain) < 2 && final != "" {
+		return // No runoffs happened; the result was already printed.
+	}
+
+	fmt.Printf("\nPoll chain:\n")
+	for i, poll := range chain {
+		maxVotes, winners := twitch.PollWinners(poll)
+		if len(winners) == 1 {
+			fmt.Printf("  %d. \"%s\": %s won with %d votes\n", i+1, poll.Title, winners[0], maxVotes)
+		} else {
+			fmt.Printf("  %d. \"%s\": tie between %s at %d votes\n", i+1, poll.Title, strings.Join(winners, ", "), maxVotes)
+		}
+	}
+
+	if final != "" {
+		fmt.Printf("\nFinal result: %s\n", final)
+	}
+}
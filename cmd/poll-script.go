@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/monktype/msc/pollscript"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+var pollRunScriptCmd = &cobra.Command{
+	Use:   "run-script <file>",
+	Short: "Run the polls in a YAML script one after another, optionally with -c (channel name), keeping score of correct answers",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		script, err := pollscript.LoadScript(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		if cmd.Flags().Changed("gap") {
+			script.Gap, err = cmd.Flags().GetDuration("gap")
+			if err != nil {
+				return err
+			}
+			if script.Gap < 0 {
+				return fmt.Errorf("--gap can't be negative")
+			}
+		}
+
+		if channelname == "" {
+			channelname = script.Channel
+		}
+		if channelname == "" {
+			fmt.Printf("Give a channel with -c or a channel in %s.\n", args[0])
+			return fmt.Errorf("no channel given")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		if err := pollscript.ResetControl(); err != nil {
+			return err
+		}
+
+		if isTerminal(os.Stdin) {
+			go readPollScriptKeys()
+			fmt.Printf("Type p and Enter to pause or resume, s and Enter to skip the current or next poll.\n")
+		}
+		fmt.Printf("Press CTRL+C during a poll to end it early; between polls it stops the script.\n\n")
+
+		return runPollScript(c, userID, channelID, script)
+	},
+}
+
+// readPollScriptKeys turns lines typed while a script runs into pause, resume, and skip.
+func readPollScriptKeys() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var err error
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "p":
+			control, cerr := pollscript.GetControl()
+			if cerr != nil {
+				err = cerr
+			} else if control.Paused {
+				err = pollscript.Resume()
+			} else {
+				err = pollscript.Pause()
+			}
+		case "s":
+			err = pollscript.Skip()
+		default:
+			fmt.Printf("Type p to pause or resume, s to skip.\n")
+		}
+		if err != nil {
+			fmt.Printf("Failed to update the script controls: %s\n", err)
+		}
+	}
+}
+
+// runPollScript runs each poll in the script in turn, waiting the gap between them, then prints and announces the score.
+func runPollScript(c helix.Client, userID string, channelID string, script pollscript.Script) error {
+	status := pollscript.Status{
+		ChannelID: channelID,
+		Total:     len(script.Polls),
+	}
+
+	for i, p := range script.Polls {
+		status.Current = i + 1
+		status.CurrentTitle = p.Title
+
+		wait := script.Gap
+		if i == 0 {
+			wait = 0
+		}
+		if skipped := waitPollScriptGap(wait, &status); skipped {
+			fmt.Printf("Skipped poll %d of %d: \"%s\"\n\n", i+1, len(script.Polls), p.Title)
+			status.Results = append(status.Results, pollscript.Result{Title: p.Title, Correct: p.Correct, Outcome: pollscript.OutcomeSkipped})
+			continue
+		}
+
+		// A script can run for a while, so pick up a refreshed token if there is one.
+		if refreshed, err := twitch.GetClient(); err == nil {
+			c = refreshed
+		}
+
+		result := runPollScriptPoll(c, userID, channelID, p, i+1, &status)
+		status.Results = append(status.Results, result)
+		saveScriptStatus(status)
+
+		fmt.Printf("\n")
+		printPollScoreboard(status.Results)
+		fmt.Printf("\n")
+	}
+
+	status.State = "done"
+	status.CurrentTitle = ""
+	saveScriptStatus(status)
+
+	correct, scored, skipped := pollscript.Tally(status.Results)
+	fmt.Printf("Script finished. Correct answers won %d of %d scored polls (%d skipped).\n", correct, scored, skipped)
+
+	if scored == 0 && script.Summary == pollscript.DefaultSummary {
+		return nil // Nothing was scored, so the default summary would only say 0 out of 0.
+	}
+
+	err := sendPollAnnouncement(c, userID, channelID, script.Summary, map[string]string{
+		"correct_count": fmt.Sprintf("%d", correct),
+		"scored":        fmt.Sprintf("%d", scored),
+		"skipped":       fmt.Sprintf("%d", skipped),
+		"polls":         fmt.Sprintf("%d", len(script.Polls)),
+	})
+	if err != nil {
+		// This isn't a fatal thing, just mention it.
+		fmt.Printf("Failed to announce the summary: %s\n", err)
+	}
+
+	return nil
+}
+
+// runPollScriptPoll announces, runs, and scores one poll of a script.
+func runPollScriptPoll(c helix.Client, userID string, channelID string, p pollscript.Poll, number int, status *pollscript.Status) pollscript.Result {
+	values := map[string]string{
+		"poll_title": p.Title,
+		"duration":   fmt.Sprintf("%d", p.Seconds()),
+		"options":    strings.Join(p.Options, ", "),
+		"number":     fmt.Sprintf("%d", number),
+		"total":      fmt.Sprintf("%d", status.Total),
+		"correct":    p.Correct,
+	}
+	failed := pollscript.Result{Title: p.Title, Correct: p.Correct, Outcome: pollscript.OutcomeFailed}
+
+	fmt.Printf("Poll %d of %d: \"%s\"\n", number, status.Total, p.Title)
+
+	if p.Before != "" {
+		if err := sendPollAnnouncement(c, userID, channelID, p.Before, values); err != nil {
+			// This isn't a fatal thing, just mention it.
+			fmt.Printf("Failed to send the before announcement but continuing with the poll: %s\n", err)
+		}
+	}
+
+	pollID, err := twitch.CreatePoll(c, channelID, p.Title, p.Seconds(), p.Options, p.ChannelPointsPerVote)
+	if err != nil {
+		fmt.Printf("Failed to start the poll, moving on: %s\n", err)
+		return failed
+	}
+
+	status.State = "running"
+	saveScriptStatus(*status)
+
+	// A skip while the poll runs ends it; the watcher then sees it end like any other poll.
+	var skipped atomic.Bool
+	finished := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-finished:
+				return
+			case <-ticker.C:
+				if skip, _ := pollscript.TakeSkip(); skip {
+					skipped.Store(true)
+					fmt.Printf("Skipping, ending the poll...\n")
+					if err := twitch.EndPoll(c, channelID, pollID); err != nil {
+						fmt.Printf("Failed to end the poll for the skip: %s\n", err)
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	fmt.Printf("Waiting for poll completion...\n")
+	poll, resultstring, err := watchPollCompletion(c, channelID, pollID)
+	close(finished)
+	if err != nil {
+		fmt.Printf("Failed to watch the poll, moving on: %s\n", err)
+		return failed
+	}
+	fmt.Printf("\n%s\n", resultstring)
+
+	if skipped.Load() {
+		return pollscript.Result{Title: p.Title, Correct: p.Correct, Outcome: pollscript.OutcomeSkipped}
+	}
+
+	result := pollscript.Score(p, poll)
+
+	if p.After != "" {
+		values["result"] = resultstring
+		values["winner"] = strings.Join(result.Winners, ", ")
+		values["outcome"] = result.Outcome
+		if err := sendPollAnnouncement(c, userID, channelID, p.After, values); err != nil {
+			// This isn't a fatal thing, just mention it.
+			fmt.Printf("Failed to send the after announcement: %s\n", err)
+		}
+	}
+
+	return result
+}
+
+// waitPollScriptGap waits before the next poll, counting down only while the script isn't paused.
+// Returns true if the poll was skipped while waiting.
+func waitPollScriptGap(wait time.Duration, status *pollscript.Status) bool {
+	paused := false
+	status.State = "waiting"
+	saveScriptStatus(*status)
+
+	if wait > 0 {
+		fmt.Printf("Next poll in %s...\n", wait)
+	}
+
+	for {
+		if skip, _ := pollscript.TakeSkip(); skip {
+			return true
+		}
+
+		control, err := pollscript.GetControl()
+		if err != nil {
+			fmt.Printf("Failed to read the script controls: %s\n", err)
+		}
+
+		if control.Paused != paused {
+			paused = control.Paused
+			if paused {
+				fmt.Printf("Paused. Resume to carry on with \"%s\".\n", status.CurrentTitle)
+				status.State = "paused"
+			} else {
+				fmt.Printf("Resumed.\n")
+				status.State = "waiting"
+			}
+			saveScriptStatus(*status)
+		}
+
+		if !paused {
+			if wait <= 0 {
+				return false
+			}
+			wait -= time.Second
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// saveScriptStatus writes the status for the API; failing to isn't worth stopping the script over.
+func saveScriptStatus(status pollscript.Status) {
+	if err := pollscript.SaveStatus(status); err != nil {
+		fmt.Printf("Failed to save the script status: %s\n", err)
+	}
+}
+
+// printPollScoreboard prints each poll's outcome so far and the running score.
+func printPollScoreboard(results []pollscript.Result) {
+	correct, scored, _ := pollscript.Tally(results)
+	fmt.Printf("Scoreboard (%d of %d correct):\n", correct, scored)
+
+	for i, result := range results {
+		line := fmt.Sprintf("  %d. \"%s\": ", i+1, result.Title)
+		switch result.Outcome {
+		case pollscript.OutcomeSkipped, pollscript.OutcomeFailed:
+			line += result.Outcome
+		case pollscript.OutcomeTie:
+			line += fmt.Sprintf("tie between %s (answer: %s)", strings.Join(result.Winners, ", "), result.Correct)
+		case pollscript.OutcomeUnscored:
+			line += strings.Join(result.Winners, ", ")
+		default:
+			line += fmt.Sprintf("%s (%s, answer: %s)", strings.Join(result.Winners, ", "), result.Outcome, result.Correct)
+		}
+		fmt.Printf("%s\n", line)
+	}
+}
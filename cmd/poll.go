@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
@@ -12,19 +15,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	defaultPollAnnouncement       = "New poll for {duration} seconds! \"{poll_title}\""
+	defaultPollResultAnnouncement = "Poll \"{poll_title}\" finished: {result}"
+)
+
 var pollCmd = &cobra.Command{
 	Use:   "poll",
 	Short: "Create a poll with -c (channel name), -d (duration in seconds), -t (title), followed by options",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 2 {
-			fmt.Printf("At least 2 poll options are required, only %d provided.\n", len(args))
-			return fmt.Errorf("give the right number of arguments")
-		}
-		if len(args) > 5 {
-			fmt.Printf("At most 5 poll options are allowed, %d provided.\n", len(args))
-			return fmt.Errorf("give the right number of arguments")
-		}
+	Long: `Create a poll with -c (channel name), -d (duration in seconds), -t (title), followed by options.
+
+An option named like a subcommand (list, get, end, watch, history, run-script) is taken as that subcommand when it
+comes first. Put -- before the options to keep them options:
 
+  msc poll -c channel -d 60 -t "Pick one" -- end continue`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		channelname, err := cmd.Flags().GetString("channel-name")
 		if err != nil {
 			return err
@@ -55,6 +60,74 @@ var pollCmd = &cobra.Command{
 			return err
 		}
 
+		channelpointsvote, err := cmd.Flags().GetInt("channel-points-vote")
+		if err != nil {
+			return err
+		}
+
+		truncate, err := cmd.Flags().GetBool("truncate")
+		if err != nil {
+			return err
+		}
+
+		if truncate {
+			var notes []string
+			title, args, notes = twitch.TruncatePoll(title, args)
+			for _, note := range notes {
+				fmt.Printf("Truncated: %s\n", note)
+			}
+		}
+
+		if err := twitch.ValidatePoll(title, duration, args, channelpointsvote); err != nil {
+			printPollViolations(err)
+			return fmt.Errorf("fix the poll and try again")
+		}
+
+		runoff, err := cmd.Flags().GetBool("runoff")
+		if err != nil {
+			return err
+		}
+
+		runoffs, err := cmd.Flags().GetInt("runoff-rounds")
+		if err != nil {
+			return err
+		}
+
+		runoffduration, err := cmd.Flags().GetInt("runoff-duration")
+		if err != nil {
+			return err
+		}
+
+		if runoff && runoffs < 1 {
+			return fmt.Errorf("--runoff-rounds must be at least 1")
+		}
+		if runoff && nowatch {
+			return fmt.Errorf("--runoff needs to watch the poll, so it can't be used with --no-watch")
+		}
+		if runoff && (runoffduration < twitch.PollMinDuration || runoffduration > twitch.PollMaxDuration) {
+			return fmt.Errorf("--runoff-duration must be %d to %d seconds", twitch.PollMinDuration, twitch.PollMaxDuration)
+		}
+
+		runoffannouncementtext, err := cmd.Flags().GetString("runoff-announcement-text")
+		if err != nil {
+			return err
+		}
+
+		tiebreakannouncementtext, err := cmd.Flags().GetString("tie-break-announcement-text")
+		if err != nil {
+			return err
+		}
+
+		announcementtext, err := cmd.Flags().GetString("announcement-text")
+		if err != nil {
+			return err
+		}
+
+		resultannouncementtext, err := cmd.Flags().GetString("result-announcement-text")
+		if err != nil {
+			return err
+		}
+
 		c, err := twitch.GetClient()
 		if err != nil {
 			return err
@@ -65,7 +138,7 @@ var pollCmd = &cobra.Command{
 			return err
 		}
 
-		pollID, err := twitch.CreatePoll(c, userID, title, duration, args)
+		pollID, err := twitch.CreatePoll(c, userID, title, duration, args, channelpointsvote)
 		if err != nil {
 			return err
 		}
@@ -76,7 +149,10 @@ var pollCmd = &cobra.Command{
 				// This isn't a fatal thing, just mention it and skip the announcement part.
 				fmt.Printf("Failed to send announcement because getting the current user failed, but continuing with the poll: %s\n", err)
 			} else {
-				err = twitch.SendAnnouncement(c, myUserID, userID, twitch.AnnouncementColorPrimary, fmt.Sprintf("New poll for %d seconds! \"%s\"", duration, title))
+				err = sendPollAnnouncement(c, myUserID, userID, announcementtext, map[string]string{
+					"poll_title": title,
+					"duration":   fmt.Sprintf("%d", duration),
+				})
 				if err != nil {
 					// This isn't a fatal thing, just mention it.
 					fmt.Printf("Failed to send announcement but continuing with the poll: %s\n", err)
@@ -88,45 +164,335 @@ var pollCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Printf("Waiting for poll completion...\n")
-		resultstring, err := watchPollCompletion(c, userID, pollID)
+		if runoff {
+			return watchPollWithRunoffs(c, userID, pollID, title, duration, pollRunoff{
+				Rounds:               runoffs,
+				Duration:             runoffduration,
+				ChannelPointsPerVote: channelpointsvote,
+				AnnouncementText:     runoffannouncementtext,
+				TieBreakText:         tiebreakannouncementtext,
+			}, sendannouncementresult, resultannouncementtext)
+		}
+
+		return watchPollAndAnnounce(c, userID, pollID, title, duration, sendannouncementresult, resultannouncementtext)
+	},
+}
+
+// printPollViolations prints each problem in a poll validation error on its own line.
+func printPollViolations(err error) {
+	validationErr, ok := err.(*twitch.PollValidationError)
+	if !ok {
+		fmt.Printf("%s\n", err)
+		return
+	}
+
+	fmt.Printf("The poll can't be created:\n")
+	for _, violation := range validationErr.Violations {
+		fmt.Printf("- %s\n", violation.Message)
+	}
+}
+
+// sendPollAnnouncement renders a poll announcement template with the poll's variables and sends it, split if it's too long.
+func sendPollAnnouncement(c helix.Client, userID string, channelID string, text string, pollValues map[string]string) error {
+	announcements, err := twitch.RenderAnnouncements(c, channelID, text, pollValues)
+	if err != nil {
+		return err
+	}
+
+	return twitch.SendAnnouncements(c, userID, channelID, twitch.AnnouncementColorPrimary, announcements, twitch.DefaultAnnouncementDelay)
+}
+
+var pollListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent polls with -c (channel name) flag",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("\n%s\n", resultstring)
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
 
-		if sendannouncementresult {
-			myUserID, err := twitch.GetMyUserID(c)
-			if err != nil {
-				// This isn't a fatal thing, just mention it and skip the announcement part.
-				fmt.Printf("Failed to send announcement result because getting the current user failed, but continuing with the poll: %s\n", err)
-			} else {
-				err = twitch.SendAnnouncement(c, myUserID, userID, twitch.AnnouncementColorPrimary, fmt.Sprintf("Poll \"%s\" finished: %s", title, resultstring))
-				if err != nil {
-					// This isn't a fatal thing, just mention it.
-					fmt.Printf("Failed to send announcement result but continuing with the poll: %s\n", err)
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		polls, err := twitch.GetPolls(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		if len(polls) == 0 {
+			fmt.Printf("No polls currently found.\n")
+			return nil
+		}
+
+		fmt.Printf("Recent polls on channel (%d):\n\n", len(polls))
+		for _, poll := range polls {
+			fmt.Printf("%s: \"%s\" (%s, %s, %d seconds)\n", poll.ID, poll.Title, poll.Status, poll.StartedAt.Local().Format("2006-01-02 15:04"), poll.Duration)
+		}
+		fmt.Printf("\n")
+
+		return nil
+	},
+}
+
+var pollGetCmd = &cobra.Command{
+	Use:   "get <poll ID>",
+	Short: "Show a poll and its votes with -c (channel name), followed by the poll ID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, _, poll, err := pollFromArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Poll \"%s\" (%s)\n", poll.Title, poll.ID)
+		fmt.Printf("Status: %s, started %s, %d seconds\n", poll.Status, poll.StartedAt.Local().Format("2006-01-02 15:04:05"), poll.Duration)
+		if poll.ChannelPointsVotingEnabled {
+			fmt.Printf("Extra votes cost %d channel points each.\n", poll.ChannelPointsPerVote)
+		}
+		fmt.Printf("\n")
+
+		printPollChoices(poll)
+
+		if poll.Status != "ACTIVE" {
+			fmt.Printf("\n%s\n", pollResultString(poll))
+		}
+
+		return nil
+	},
+}
+
+var pollEndCmd = &cobra.Command{
+	Use:   "end <poll ID>",
+	Short: "End a poll early with -c (channel name), followed by the poll ID; --archive also hides the results",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := cmd.Flags().GetBool("archive")
+		if err != nil {
+			return err
+		}
+
+		c, channelID, poll, err := pollFromArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if archive {
+			err = twitch.ArchivePoll(c, channelID, poll.ID)
+		} else {
+			err = twitch.EndPoll(c, channelID, poll.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if archive {
+			fmt.Printf("Poll \"%s\" ended and archived.\n", poll.Title)
+		} else {
+			fmt.Printf("Poll \"%s\" ended.\n", poll.Title)
+		}
+		return nil
+	},
+}
+
+var pollWatchCmd = &cobra.Command{
+	Use:   "watch <poll ID>",
+	Short: "Watch a running poll until it ends with -c (channel name), followed by the poll ID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sendannouncementresult, err := cmd.Flags().GetBool("send-announcement-result")
+		if err != nil {
+			return err
+		}
+
+		resultannouncementtext, err := cmd.Flags().GetString("result-announcement-text")
+		if err != nil {
+			return err
+		}
+
+		c, channelID, poll, err := pollFromArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if poll.Status != "ACTIVE" {
+			fmt.Printf("Poll \"%s\" already ended (%s).\n\n", poll.Title, poll.Status)
+			printPollChoices(poll)
+			fmt.Printf("\n%s\n", pollResultString(poll))
+			return nil
+		}
+
+		return watchPollAndAnnounce(c, channelID, poll.ID, poll.Title, poll.Duration, sendannouncementresult, resultannouncementtext)
+	},
+}
+
+var pollHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Export past polls with winners and turnout with -c (channel name) to a file (or stdout if no file is given)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		sinceflag, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		if format == "" {
+			format = "markdown"
+			if len(args) == 1 {
+				switch strings.ToLower(filepath.Ext(args[0])) {
+				case ".csv":
+					format = "csv"
+				case ".json":
+					format = "json"
 				}
 			}
 		}
+		if !slices.Contains(twitch.PollHistoryFormats, format) {
+			fmt.Printf("Format can only be %s.\n", strings.Join(twitch.PollHistoryFormats, ", "))
+			return fmt.Errorf("format string is incorrect")
+		}
+
+		since, err := twitch.ParseSince(sinceflag)
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		polls, err := twitch.GetPollHistory(c, channelID, since)
+		if err != nil {
+			return err
+		}
+
+		summaries := make([]twitch.PollSummary, 0, len(polls))
+		for _, poll := range polls {
+			summaries = append(summaries, twitch.SummarizePoll(poll))
+		}
+
+		out := os.Stdout
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				fmt.Printf("Failed to create %s: %s\n", args[0], err)
+				return err
+			}
+			defer out.Close()
+		}
+
+		err = twitch.WritePollHistory(out, summaries, format)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("Exported %d polls to %s\n", len(summaries), args[0])
+		}
 
 		return nil
 	},
 }
 
+// pollFromArgs gets a client and the poll named by the -c flag and the poll ID argument.
+func pollFromArgs(cmd *cobra.Command, args []string) (helix.Client, string, helix.Poll, error) {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return helix.Client{}, "", helix.Poll{}, err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return helix.Client{}, "", helix.Poll{}, err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return c, "", helix.Poll{}, err
+	}
+
+	poll, err := twitch.GetPoll(c, channelID, args[0])
+	if err != nil {
+		return c, channelID, poll, err
+	}
+	if poll.ID == "" {
+		return c, channelID, poll, fmt.Errorf("poll %s not found", args[0])
+	}
+
+	return c, channelID, poll, nil
+}
+
+// watchPollAndAnnounce watches a poll until it ends, prints the result, and announces it if announceResult is set.
+func watchPollAndAnnounce(c helix.Client, channelID string, pollID string, title string, duration int, announceResult bool, resultTemplate string) error {
+	fmt.Printf("Waiting for poll completion...\n")
+	_, resultstring, err := watchPollCompletion(c, channelID, pollID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n", resultstring)
+
+	if announceResult {
+		myUserID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			// This isn't a fatal thing, just mention it and skip the announcement part.
+			fmt.Printf("Failed to send announcement result because getting the current user failed, but continuing with the poll: %s\n", err)
+		} else {
+			err = sendPollAnnouncement(c, myUserID, channelID, resultTemplate, map[string]string{
+				"poll_title": title,
+				"duration":   fmt.Sprintf("%d", duration),
+				"result":     resultstring,
+			})
+			if err != nil {
+				// This isn't a fatal thing, just mention it.
+				fmt.Printf("Failed to send announcement result but continuing with the poll: %s\n", err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // --- Code here is for watching for responses in the CLI ---
 
 type WatchPollResult struct {
+	Poll   helix.Poll // The finished poll
 	Result string
 	Error  error
 }
 
 // Internal watchPollCompletion worker function (for terminating the poll)
 // Re-using watchPollResult as a result, but only the Error component is going to be used.
-func watchPollCompletionTerminationWorker(c helix.Client, channelID string, pollID string, resultChan chan<- WatchPollResult, doneChan <-chan os.Signal) {
+func watchPollCompletionTerminationWorker(c helix.Client, channelID string, pollID string, resultChan chan<- WatchPollResult, doneChan <-chan os.Signal, finished <-chan struct{}) {
 	defer close(resultChan)
 	for {
 		select {
+		case <-finished:
+			// The poll ended on its own; nothing to terminate.
+			return
 		case <-doneChan:
 			fmt.Printf("Terminating poll...\n")
 			err := twitch.EndPoll(c, channelID, pollID)
@@ -137,9 +503,21 @@ func watchPollCompletionTerminationWorker(c helix.Client, channelID string, poll
 }
 
 // Internal watchPollCompletion worker function
-// NOTE: It could be broken into some smaller functions if desired, but not critical right now.
+// Live updates come over EventSub; if that can't be set up (or drops), it falls back to checking the poll every second.
 func watchPollCompletionWorker(c helix.Client, channelID string, pollID string, resultChan chan<- WatchPollResult) {
 	defer close(resultChan)
+
+	err := watchPollEvents(c, channelID, pollID, resultChan)
+	if err == nil {
+		return
+	}
+	fmt.Printf("Live poll updates aren't available (%s), checking the poll every second instead.\n", err)
+
+	watchPollCompletionPolling(c, channelID, pollID, resultChan)
+}
+
+// watchPollCompletionPolling checks the poll with GetPoll every second until it ends, then sends the result.
+func watchPollCompletionPolling(c helix.Client, channelID string, pollID string, resultChan chan<- WatchPollResult) {
 	pollGetFailCount := 0
 	for {
 		// Fetch the poll status
@@ -159,39 +537,7 @@ func watchPollCompletionWorker(c helix.Client, channelID string, pollID string,
 		if poll.ID != "" {
 			// Check if the poll is completed
 			if poll.Status != "ACTIVE" { // There are many statuses that mean "not running," but "ACTIVE" is "running"
-				fmt.Printf("Poll completed! Here are the results for \"%s\":\n", poll.Title)
-
-				maxVotes := 0
-				var winningOptions []string
-
-				for _, option := range poll.Choices {
-					fmt.Printf("Option: %s, Votes: %d\n", option.Title, option.Votes)
-					if option.Votes > maxVotes {
-						maxVotes = option.Votes
-						winningOptions = []string{option.Title}
-					} else if option.Votes == maxVotes {
-						winningOptions = append(winningOptions, option.Title)
-					}
-				}
-
-				fmt.Printf("\n")
-
-				var resultstring string
-
-				if len(winningOptions) == 1 {
-					resultstring = fmt.Sprintf("The winning option (at %d votes) is: %s", maxVotes, winningOptions[0])
-				} else {
-					resultstring = fmt.Sprintf("The top tie options (at %d votes) are:", maxVotes)
-					for i := range winningOptions {
-						if i == 0 {
-							resultstring = resultstring + fmt.Sprintf(" %s", winningOptions[i])
-						} else {
-							resultstring = resultstring + fmt.Sprintf("; %s", winningOptions[i])
-						}
-					}
-				}
-
-				resultChan <- WatchPollResult{Result: resultstring, Error: nil}
+				sendPollResult(poll, resultChan)
 				return
 			}
 		} else /* this triggers if `poll.ID == ""` */ {
@@ -202,18 +548,54 @@ func watchPollCompletionWorker(c helix.Client, channelID string, pollID string,
 	}
 }
 
+// printPollChoices prints each choice's votes, split into regular and channel points votes if the poll allowed them.
+func printPollChoices(poll helix.Poll) {
+	for _, option := range poll.Choices {
+		if poll.ChannelPointsVotingEnabled {
+			// Votes is the total; channel points votes are the extra ones bought on top of each viewer's free vote.
+			fmt.Printf("Option: %s, Votes: %d (%d regular, %d with channel points)\n", option.Title, option.Votes, option.Votes-option.ChannelPointsVotes, option.ChannelPointsVotes)
+		} else {
+			fmt.Printf("Option: %s, Votes: %d\n", option.Title, option.Votes)
+		}
+	}
+}
+
+// pollResultString describes the winner, or the tie, of a finished poll.
+func pollResultString(poll helix.Poll) string {
+	maxVotes, winningOptions := twitch.PollWinners(poll)
+
+	var resultstring string
+
+	if len(winningOptions) == 1 {
+		resultstring = fmt.Sprintf("The winning option (at %d votes) is: %s", maxVotes, winningOptions[0])
+	} else {
+		resultstring = fmt.Sprintf("The top tie options (at %d votes) are:", maxVotes)
+		for i := range winningOptions {
+			if i == 0 {
+				resultstring = resultstring + fmt.Sprintf(" %s", winningOptions[i])
+			} else {
+				resultstring = resultstring + fmt.Sprintf("; %s", winningOptions[i])
+			}
+		}
+	}
+
+	return resultstring
+}
+
 // watchPollCompletion checks the status of the poll and prints the results when completed.
-func watchPollCompletion(c helix.Client, channelID string, pollID string) (string, error) {
+// Returns the finished poll, its result string, and error.
+func watchPollCompletion(c helix.Client, channelID string, pollID string) (helix.Poll, string, error) {
 	resultChan := make(chan WatchPollResult)
 	termResultChan := make(chan WatchPollResult)
 	doneChan := make(chan os.Signal, 1)
 	signal.Notify(doneChan, syscall.SIGINT, syscall.SIGTERM)
+	finished := make(chan struct{})
 
 	// Start the termination worker
 	// It catches a ctrl+c and sends it over doneChan.
 	// That then sends a terminate to Twitch, which will then picked up by the other goroutine
 	// and cause it to naturally close / look for results / etc.
-	go watchPollCompletionTerminationWorker(c, channelID, pollID, termResultChan, doneChan)
+	go watchPollCompletionTerminationWorker(c, channelID, pollID, termResultChan, doneChan, finished)
 
 	// Start the regular worker
 	// It gathers results and returns them as soon as the poll is not "ACTIVE" anymore.
@@ -224,12 +606,18 @@ func watchPollCompletion(c helix.Client, channelID string, pollID string) (strin
 	for {
 		select {
 		case result := <-resultChan:
-			close(doneChan)
+			// Polls can be watched one after another (runoffs, scripts), so leave no CTRL+C handling behind.
+			signal.Stop(doneChan)
+			close(finished)
 			if result.Error != nil {
-				return "", result.Error
+				return helix.Poll{}, "", result.Error
+			}
+			return result.Poll, result.Result, nil
+		case termResult, ok := <-termResultChan:
+			if !ok {
+				termResultChan = nil // The termination worker is done; stop selecting on it.
+				continue
 			}
-			return result.Result, nil
-		case termResult := <-termResultChan:
 			if termResult.Error != nil {
 				fmt.Printf("Failed to terminate. If you see this, CTRL+C more to terminate the program or wait for the poll to finish. %s\n", termResult.Error)
 			}
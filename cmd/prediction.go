@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+var predictionCmd = &cobra.Command{
+	Use:   "prediction",
+	Short: "Create, watch, lock, resolve, cancel, or list predictions with -c (channel name) flag",
+}
+
+var predictionCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a prediction with -c (channel name), -d (prediction window in seconds), -t (title), followed by outcomes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			fmt.Printf("At least 2 prediction outcomes are required, only %d provided.\n", len(args))
+			return fmt.Errorf("give the right number of arguments")
+		}
+		if len(args) > 10 {
+			fmt.Printf("At most 10 prediction outcomes are allowed, %d provided.\n", len(args))
+			return fmt.Errorf("give the right number of arguments")
+		}
+
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		title, err := cmd.Flags().GetString("title")
+		if err != nil {
+			return err
+		}
+
+		duration, err := cmd.Flags().GetInt("duration")
+		if err != nil {
+			return err
+		}
+
+		nowatch, err := cmd.Flags().GetBool("no-watch")
+		if err != nil {
+			return err
+		}
+
+		if utf8.RuneCountInString(title) > 45 {
+			fmt.Printf("The title can be at most 45 characters, it's %d.\n", utf8.RuneCountInString(title))
+			return fmt.Errorf("title is too long")
+		}
+		for _, outcome := range args {
+			if utf8.RuneCountInString(outcome) > 25 {
+				fmt.Printf("Outcome \"%s\" is over the 25 character limit.\n", outcome)
+				return fmt.Errorf("outcome is too long")
+			}
+		}
+		if duration < 30 || duration > 1800 {
+			fmt.Printf("The prediction window has to be between 30 and 1800 seconds.\n")
+			return fmt.Errorf("duration is out of range")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		predictionID, err := twitch.CreatePrediction(c, channelID, title, duration, args)
+		if err != nil {
+			return err
+		}
+
+		if nowatch {
+			return nil
+		}
+
+		return watchPrediction(c, channelID, predictionID)
+	},
+}
+
+var predictionWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch a running prediction with -c (channel name), -i (prediction ID) until it locks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, channelID, prediction, err := predictionFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		return watchPrediction(c, channelID, prediction.ID)
+	},
+}
+
+var predictionLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock a prediction early with -c (channel name), -i (prediction ID)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, channelID, prediction, err := predictionFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		prediction, err = twitch.LockPrediction(c, channelID, prediction.ID)
+		if err != nil {
+			return err
+		}
+
+		printPredictionStandings(prediction)
+		return nil
+	},
+}
+
+var predictionResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Pay out a prediction with -c (channel name), -i (prediction ID), -w (winning outcome: title, number, or ID)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		winner, err := cmd.Flags().GetString("winner")
+		if err != nil {
+			return err
+		}
+
+		c, channelID, prediction, err := predictionFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		outcomeID := ""
+		for i, outcome := range prediction.Outcomes {
+			if outcome.ID == winner || strings.EqualFold(outcome.Title, winner) || strconv.Itoa(i+1) == winner {
+				outcomeID = outcome.ID
+				break
+			}
+		}
+		if outcomeID == "" {
+			fmt.Printf("No outcome matches %s; use the outcome's title, its number (1-%d), or its ID.\n", winner, len(prediction.Outcomes))
+			return fmt.Errorf("unknown outcome")
+		}
+
+		prediction, err = twitch.ResolvePrediction(c, channelID, prediction.ID, outcomeID)
+		if err != nil {
+			return err
+		}
+
+		printPredictionStandings(prediction)
+		return nil
+	},
+}
+
+var predictionCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel a prediction and refund everyone with -c (channel name), -i (prediction ID)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, channelID, prediction, err := predictionFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, err = twitch.CancelPrediction(c, channelID, prediction.ID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Prediction \"%s\" canceled; channel points were refunded.\n", prediction.Title)
+		return nil
+	},
+}
+
+var predictionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent predictions with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		predictions, err := twitch.GetPredictions(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		if len(predictions) == 0 {
+			fmt.Printf("No predictions currently found.\n")
+			return nil
+		}
+
+		fmt.Printf("Recent predictions on channel (%d):\n\n", len(predictions))
+		for _, prediction := range predictions {
+			fmt.Printf("%s: \"%s\" (%s, %s)\n", prediction.ID, prediction.Title, prediction.Status, prediction.CreatedAt.Local().Format("2006-01-02 15:04"))
+			for i, outcome := range prediction.Outcomes {
+				winner := ""
+				if outcome.ID == prediction.WinningOutcomeID {
+					winner = " (winner)"
+				}
+				fmt.Printf("\t%d) %s: %d points from %d users%s\n", i+1, outcome.Title, outcome.ChannelPoints, outcome.Users, winner)
+			}
+		}
+		fmt.Printf("\n")
+
+		return nil
+	},
+}
+
+// predictionFromFlags gets a client and the prediction named by the -c and -i flags.
+func predictionFromFlags(cmd *cobra.Command) (helix.Client, string, helix.Prediction, error) {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return helix.Client{}, "", helix.Prediction{}, err
+	}
+
+	predictionID, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return helix.Client{}, "", helix.Prediction{}, err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return helix.Client{}, "", helix.Prediction{}, err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return c, "", helix.Prediction{}, err
+	}
+
+	prediction, err := twitch.GetPrediction(c, channelID, predictionID)
+	if err != nil {
+		return c, channelID, prediction, err
+	}
+	if prediction.ID == "" {
+		return c, channelID, prediction, fmt.Errorf("prediction %s not found", predictionID)
+	}
+
+	return c, channelID, prediction, nil
+}
+
+// --- Code here is for watching predictions in the CLI ---
+
+// predictionOdds is the payout ratio for an outcome (1:odds), or 0 if nobody has predicted it.
+func predictionOdds(prediction helix.Prediction, outcome helix.Outcomes) float64 {
+	if outcome.ChannelPoints == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, o := range prediction.Outcomes {
+		total += o.ChannelPoints
+	}
+
+	return float64(total) / float64(outcome.ChannelPoints)
+}
+
+func predictionProgressLine(prediction helix.Prediction) string {
+	total := 0
+	for _, outcome := range prediction.Outcomes {
+		total += outcome.ChannelPoints
+	}
+
+	var parts []string
+	for _, outcome := range prediction.Outcomes {
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(outcome.ChannelPoints) / float64(total)
+		}
+		odds := "-"
+		if o := predictionOdds(prediction, outcome); o > 0 {
+			odds = fmt.Sprintf("1:%.2f", o)
+		}
+		parts = append(parts, fmt.Sprintf("%s %d (%.0f%%, %s)", outcome.Title, outcome.ChannelPoints, percent, odds))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func printPredictionStandings(prediction helix.Prediction) {
+	total := 0
+	for _, outcome := range prediction.Outcomes {
+		total += outcome.ChannelPoints
+	}
+
+	fmt.Printf("Prediction \"%s\" is %s. %d channel points in total:\n", prediction.Title, prediction.Status, total)
+
+	for i, outcome := range prediction.Outcomes {
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(outcome.ChannelPoints) / float64(total)
+		}
+		odds := "no predictions"
+		if o := predictionOdds(prediction, outcome); o > 0 {
+			odds = fmt.Sprintf("odds 1:%.2f", o)
+		}
+		winner := ""
+		if outcome.ID == prediction.WinningOutcomeID {
+			winner = " WINNER"
+		}
+		fmt.Printf("%d) %s: %d points from %d users (%.1f%%, %s)%s\n", i+1, outcome.Title, outcome.ChannelPoints, outcome.Users, percent, odds, winner)
+
+		predictors := outcome.TopPredictors
+		sort.Slice(predictors, func(a, b int) bool { return predictors[a].ChannelPointsUsed > predictors[b].ChannelPointsUsed })
+		for j, predictor := range predictors {
+			if j == 5 {
+				break
+			}
+			if prediction.Status == "RESOLVED" && outcome.ID == prediction.WinningOutcomeID {
+				fmt.Printf("\t%s: %d points, won %d\n", predictor.UserName, predictor.ChannelPointsUsed, predictor.ChannelPointsWon)
+			} else {
+				fmt.Printf("\t%s: %d points\n", predictor.UserName, predictor.ChannelPointsUsed)
+			}
+		}
+	}
+}
+
+// watchPrediction prints the prediction's standings as they change until it locks (or ends), then the full standings.
+// CTRL+C locks the prediction early, like watchPollCompletion ends a poll early.
+func watchPrediction(c helix.Client, channelID string, predictionID string) error {
+	doneChan := make(chan os.Signal, 1)
+	signal.Notify(doneChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(doneChan)
+
+	fmt.Printf("Waiting for the prediction to lock...\n")
+	fmt.Printf("Press CTRL+C once to lock the prediction early.\n\n")
+
+	lastLine := ""
+	predictionGetFailCount := 0
+	for {
+		prediction, err := twitch.GetPrediction(c, channelID, predictionID)
+		if err != nil {
+			predictionGetFailCount = predictionGetFailCount + 1
+			if predictionGetFailCount > 2 {
+				return err
+			}
+			fmt.Printf("Trying again...\n")
+		} else if prediction.ID != "" {
+			predictionGetFailCount = 0
+
+			if prediction.Status != "ACTIVE" {
+				fmt.Printf("\n")
+				printPredictionStandings(prediction)
+				if prediction.Status == "LOCKED" {
+					fmt.Printf("\nPay it out with `msc prediction resolve -c <channel> -i %s -w <outcome>`, or refund with `cancel`.\n", prediction.ID)
+				}
+				return nil
+			}
+
+			left := time.Until(prediction.CreatedAt.Add(time.Duration(prediction.PredictionWindow) * time.Second)).Round(time.Second)
+			line := predictionProgressLine(prediction)
+			if line != lastLine {
+				fmt.Printf("[%s left] %s\n", left, line)
+				lastLine = line
+			}
+		}
+
+		select {
+		case <-doneChan:
+			fmt.Printf("Locking prediction...\n")
+			if _, err := twitch.LockPrediction(c, channelID, predictionID); err != nil {
+				fmt.Printf("Failed to lock. If you see this, CTRL+C more to lock again or wait for the prediction window to end. %s\n", err)
+			}
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
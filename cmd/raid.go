@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+const defaultRaidAnnouncement = "We're raiding {target}, who's playing {target_game}! Thanks for hanging out!"
+
+var raidCmd = &cobra.Command{
+	Use:   "raid",
+	Short: "Start or cancel a raid with -c (channel name) flag",
+}
+
+var raidStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Raid -t (target) from -c (channel name); the target has to be live unless --force",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		targetname, err := cmd.Flags().GetString("target")
+		if err != nil {
+			return err
+		}
+
+		announce, err := cmd.Flags().GetString("announce")
+		if err != nil {
+			return err
+		}
+
+		userColor, err := cmd.Flags().GetString("border-color")
+		if err != nil {
+			return err
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		selectedColor, err := parseAnnouncementColor(userColor)
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		targetID, err := twitch.GetUserID(c, targetname)
+		if err != nil {
+			return err
+		}
+
+		stream, live, err := twitch.GetStream(c, targetID)
+		if err != nil {
+			return err
+		}
+
+		values := map[string]string{"target": targetname}
+		if live {
+			fmt.Printf("%s is live with %d viewers, playing %s: %s\n", stream.UserName, stream.ViewerCount, stream.GameName, stream.Title)
+			values["target"] = stream.UserName
+			values["target_game"] = stream.GameName
+			values["target_title"] = stream.Title
+			values["target_viewers"] = fmt.Sprintf("%d", stream.ViewerCount)
+		} else {
+			if !force {
+				fmt.Printf("%s isn't live. Use --force to raid them anyway.\n", targetname)
+				return fmt.Errorf("raid target is offline")
+			}
+			fmt.Printf("%s isn't live; raiding anyway.\n", targetname)
+
+			info, err := twitch.GetChannelInformation(c, targetID)
+			if err != nil {
+				return err
+			}
+			values["target"] = info.BroadcasterName
+			values["target_game"] = info.GameName
+			values["target_title"] = info.Title
+			values["target_viewers"] = "0"
+		}
+
+		if announce != "" {
+			userID, err := twitch.GetMyUserID(c)
+			if err != nil {
+				return err
+			}
+
+			announcements, err := twitch.RenderAnnouncements(c, channelID, announce, values)
+			if err != nil {
+				return err
+			}
+
+			err = twitch.SendAnnouncements(c, userID, channelID, selectedColor, announcements, twitch.DefaultAnnouncementDelay)
+			if err != nil {
+				// This isn't a fatal thing, just mention it.
+				fmt.Printf("Failed to send announcement but continuing with the raid: %s\n", err)
+			}
+		}
+
+		err = twitch.StartRaid(c, channelID, targetID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Raid to %s started; it goes in 90 seconds, or when you click \"Raid Now\". `msc raid cancel` stops it.\n", values["target"])
+		return nil
+	},
+}
+
+var raidCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel the pending raid from -c (channel name)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		err = twitch.CancelRaid(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Raid canceled.\n")
+		return nil
+	},
+}
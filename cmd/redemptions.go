@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/monktype/msc/redemptions"
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var redemptionsCmd = &cobra.Command{
+	Use:   "redemptions",
+	Short: "Process channel point redemptions automatically",
+}
+
+var redemptionsServeCmd = &cobra.Command{
+	Use:   "serve <file>",
+	Short: "Run the actions in a YAML config file for each new redemption, optionally with -c (channel name), until CTRL+C",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		config, err := redemptions.LoadConfig(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		if channelname == "" {
+			channelname = config.Channel
+		}
+		if channelname == "" {
+			fmt.Printf("Give a channel with -c or a channel in %s.\n", args[0])
+			return fmt.Errorf("no channel given")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		processor, err := redemptions.NewProcessor(c, config, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		fmt.Printf("Processing redemptions on %s with %d actions.\n", channelname, len(config.Actions))
+		if logPath, err := redemptions.LogPath(); err == nil {
+			fmt.Printf("Outcomes are logged to %s\n", logPath)
+		}
+		fmt.Printf("Press CTRL+C to stop.\n\n")
+
+		return processor.Run(stop)
+	},
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+// redemptionRewards gets the rewards whose redemptions can be read and changed, which is only the ones msc created.
+// An empty rewardID means all of them.
+func redemptionRewards(c helix.Client, channelID string, rewardID string) ([]helix.ChannelCustomReward, error) {
+	manageable, err := twitch.GetManageableRewards(c, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rewardID == "" {
+		return manageable, nil
+	}
+
+	for _, reward := range manageable {
+		if reward.ID == rewardID {
+			return []helix.ChannelCustomReward{reward}, nil
+		}
+	}
+
+	fmt.Printf("Reward %s wasn't found, or wasn't created by msc so its redemptions can't be managed.\n", rewardID)
+	return nil, fmt.Errorf("reward not found")
+}
+
+// redemptionFilter picks which redemptions a bulk fulfill or cancel touches. Zero values match everything.
+type redemptionFilter struct {
+	Before time.Time
+	User   string
+	Input  *regexp.Regexp
+}
+
+func redemptionFilterFromFlags(cmd *cobra.Command) (redemptionFilter, error) {
+	var filter redemptionFilter
+
+	olderthan, err := cmd.Flags().GetString("older-than")
+	if err != nil {
+		return filter, err
+	}
+	if olderthan != "" {
+		filter.Before, err = twitch.ParseSince(olderthan)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return filter, err
+		}
+	}
+
+	filter.User, err = cmd.Flags().GetString("user")
+	if err != nil {
+		return filter, err
+	}
+
+	inputmatch, err := cmd.Flags().GetString("input-match")
+	if err != nil {
+		return filter, err
+	}
+	if inputmatch != "" {
+		filter.Input, err = regexp.Compile(inputmatch)
+		if err != nil {
+			fmt.Printf("--input-match isn't a valid regular expression: %s\n", err)
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+func (f redemptionFilter) matches(redemption helix.ChannelCustomRewardsRedemption) bool {
+	if !f.Before.IsZero() && !redemption.RedeemedAt.Time.Before(f.Before) {
+		return false
+	}
+	if f.User != "" && !strings.EqualFold(redemption.UserLogin, f.User) && !strings.EqualFold(redemption.UserName, f.User) {
+		return false
+	}
+	if f.Input != nil && !f.Input.MatchString(redemption.UserInput) {
+		return false
+	}
+	return true
+}
+
+// bulkUpdateRedemptions is fulfill --all and cancel --all: it finds every unfulfilled redemption that matches the
+// filter flags, asks, then sets them to status in batches of twitch.RedemptionBatchSize.
+func bulkUpdateRedemptions(cmd *cobra.Command, status string) error {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return err
+	}
+
+	rewardid, err := cmd.Flags().GetString("reward")
+	if err != nil {
+		return err
+	}
+
+	dryrun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+
+	filter, err := redemptionFilterFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	verb := "fulfill"
+	if status == "CANCELED" {
+		verb = "cancel (and refund)"
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return err
+	}
+
+	channelid, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return err
+	}
+
+	rewards, err := redemptionRewards(c, channelid, rewardid)
+	if err != nil {
+		return err
+	}
+
+	matched := make(map[string][]string)
+	total := 0
+	for _, reward := range rewards {
+		redemptions, err := twitch.ListRedemptions(c, channelid, reward.ID, "UNFULFILLED", "OLDEST", 0)
+		if err != nil {
+			return err
+		}
+
+		for _, redemption := range redemptions {
+			if filter.matches(redemption) {
+				matched[reward.ID] = append(matched[reward.ID], redemption.ID)
+			}
+		}
+
+		if len(matched[reward.ID]) != 0 {
+			fmt.Printf("%s: %d of %d unfulfilled redemptions match\n", reward.Title, len(matched[reward.ID]), len(redemptions))
+			total += len(matched[reward.ID])
+		}
+	}
+
+	if total == 0 {
+		fmt.Printf("No unfulfilled redemptions match.\n")
+		return nil
+	}
+
+	if dryrun {
+		fmt.Printf("\nDry run; nothing was changed.\n")
+		return nil
+	}
+
+	if !yes && !askConfirmation(fmt.Sprintf("\n%s %d redemptions?", strings.ToUpper(verb[:1])+verb[1:], total)) {
+		fmt.Printf("Not changing any redemptions.\n")
+		return nil
+	}
+
+	updated, failed := 0, 0
+	fmt.Printf("\n")
+	for _, reward := range rewards {
+		ids := matched[reward.ID]
+		if len(ids) == 0 {
+			continue
+		}
+
+		rewardUpdated, rewardFailed := 0, 0
+		for start := 0; start < len(ids); start += twitch.RedemptionBatchSize {
+			end := min(start+twitch.RedemptionBatchSize, len(ids))
+
+			result, err := twitch.UpdateRedemptionStatuses(c, channelid, reward.ID, ids[start:end], status)
+			if err != nil {
+				rewardFailed += end - start
+				continue
+			}
+			// Twitch leaves out redemptions that were already fulfilled or canceled in the meantime.
+			rewardUpdated += len(result)
+			rewardFailed += end - start - len(result)
+		}
+
+		fmt.Printf("%s: %d updated, %d failed\n", reward.Title, rewardUpdated, rewardFailed)
+		updated += rewardUpdated
+		failed += rewardFailed
+	}
+
+	fmt.Printf("\nSet %d of %d redemptions to %s across %d rewards.\n", updated, total, status, len(matched))
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d redemptions couldn't be set to %s", failed, total, status)
+	}
+
+	return nil
+}
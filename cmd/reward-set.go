@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/monktype/msc/rewardset"
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var rewardsapplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make the channel's rewards match a YAML reward set with -c (channel name), followed by the file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		dryrun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		prune, err := cmd.Flags().GetBool("prune")
+		if err != nil {
+			return err
+		}
+
+		disableinstead, err := cmd.Flags().GetBool("disable-instead-of-delete")
+		if err != nil {
+			return err
+		}
+
+		if disableinstead && !prune {
+			fmt.Printf("--disable-instead-of-delete only changes what --prune does; add --prune.\n")
+			return fmt.Errorf("missing --prune")
+		}
+
+		set, err := rewardset.LoadSet(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		all, err := twitch.GetRewards(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		manageable, err := twitch.GetManageableRewards(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		plan := rewardset.Plan(set, all, manageable, prune, disableinstead)
+
+		changes := 0
+		for _, action := range plan {
+			switch action.Kind {
+			case rewardset.ActionCreate:
+				fmt.Printf("+ create %s (%d)\n", action.Title, action.Desired.Cost)
+			case rewardset.ActionUpdate:
+				fmt.Printf("~ update %s\n", action.Title)
+				for _, change := range action.Changes {
+					fmt.Printf("    %s\n", change)
+				}
+			case rewardset.ActionDelete:
+				fmt.Printf("- delete %s\n", action.Title)
+			case rewardset.ActionDisable:
+				fmt.Printf("- disable %s\n", action.Title)
+			case rewardset.ActionConflict:
+				fmt.Printf("! skip %s: a reward with this title exists but wasn't created by msc, so it can't be changed\n", action.Title)
+				continue
+			}
+			changes++
+		}
+
+		if changes == 0 {
+			fmt.Printf("Rewards already match %s.\n", args[0])
+			return nil
+		}
+
+		if dryrun {
+			fmt.Printf("\nDry run; nothing was changed.\n")
+			return nil
+		}
+
+		if !yes && !askConfirmation("\nApply these changes?") {
+			fmt.Printf("Not applying rewards.\n")
+			return nil
+		}
+
+		failed := 0
+		for _, action := range plan {
+			if action.Kind == rewardset.ActionConflict {
+				continue
+			}
+
+			if err := action.Apply(c, channelID); err != nil {
+				fmt.Printf("Failed to %s %s: %s\n", action.Kind, action.Title, err)
+				failed++
+				continue
+			}
+
+			// CreateReward and DeleteReward print their own line.
+			switch action.Kind {
+			case rewardset.ActionUpdate:
+				fmt.Printf("Updated reward %s\n", action.Title)
+			case rewardset.ActionDisable:
+				fmt.Printf("Disabled reward %s\n", action.Title)
+			}
+		}
+
+		if failed != 0 {
+			return fmt.Errorf("%d of %d changes failed", failed, changes)
+		}
+
+		return nil
+	},
+}
+
+var rewardsexportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the channel's rewards as a reward set for `reward apply` to a file (or stdout if no file is given)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := twitch.GetRewards(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		var set rewardset.Set
+		for _, reward := range rewards {
+			set.Rewards = append(set.Rewards, rewardset.FromReward(reward))
+		}
+
+		out := os.Stdout
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				fmt.Printf("Failed to create %s: %s\n", args[0], err)
+				return err
+			}
+			defer out.Close()
+		}
+
+		err = rewardset.WriteSet(out, set)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("Exported %d rewards to %s\n", len(set.Rewards), args[0])
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/monktype/msc/eventsub"
+	"github.com/monktype/msc/rewardstats"
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var rewardsstatsCmd = &cobra.Command{
+	Use:   "stats [file]",
+	Short: "Show recorded redemption stats per reward and top redeemers with -c (channel name), to a file or stdout",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		sinceflag, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return err
+		}
+
+		untilflag, err := cmd.Flags().GetString("until")
+		if err != nil {
+			return err
+		}
+
+		top, err := cmd.Flags().GetInt("top")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		if format == "" {
+			format = "table"
+			if len(args) == 1 {
+				switch strings.ToLower(filepath.Ext(args[0])) {
+				case ".csv":
+					format = "csv"
+				case ".json":
+					format = "json"
+				}
+			}
+		}
+		if !slices.Contains(rewardstats.Formats, format) {
+			fmt.Printf("Format can only be %s.\n", strings.Join(rewardstats.Formats, ", "))
+			return fmt.Errorf("format string is incorrect")
+		}
+
+		since, until, err := rewardstats.ParseWindow(sinceflag, untilflag)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		redemptions, err := rewardstats.Load(channelID)
+		if err != nil {
+			return err
+		}
+		if len(redemptions) == 0 {
+			fmt.Printf("No redemptions recorded on %s yet; run msc reward stats backfill or msc reward stats record.\n", channelname)
+			return nil
+		}
+
+		out := os.Stdout
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				fmt.Printf("Failed to create %s: %s\n", args[0], err)
+				return err
+			}
+			defer out.Close()
+		}
+
+		err = rewardstats.WriteStats(out, rewardstats.Compute(redemptions, since, until, top), format)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("Wrote redemption stats to %s\n", args[0])
+		}
+
+		return nil
+	},
+}
+
+var rewardsstatsBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Record every redemption Twitch still has for -c (channel name)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		count, err := rewardstats.Backfill(c, channelID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Recorded %d redemptions.\n", count)
+		return nil
+	},
+}
+
+var rewardsstatsRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Backfill, then record new redemptions and their fulfills and refunds on -c (channel name) until CTRL+C",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		session, err := eventsub.Connect()
+		if err != nil {
+			return err
+		}
+
+		if err := twitch.SubscribeRedemptionEvents(c, session.ID, channelID); err != nil {
+			session.Close()
+			return err
+		}
+		if err := twitch.SubscribeRedemptionUpdateEvents(c, session.ID, channelID); err != nil {
+			session.Close()
+			return err
+		}
+
+		// Subscribe first so nothing redeemed during the backfill is missed; recording one twice only updates it.
+		count, err := rewardstats.Backfill(c, channelID)
+		if err != nil {
+			session.Close()
+			return err
+		}
+		fmt.Printf("Backfilled %d redemptions.\n", count)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			session.Close()
+		}()
+
+		fmt.Printf("Recording redemptions on %s. Press CTRL+C to stop.\n\n", channelname)
+
+		return session.Listen(func(n eventsub.Notification) {
+			if n.Type != "channel.channel_points_custom_reward_redemption.add" && n.Type != "channel.channel_points_custom_reward_redemption.update" {
+				return
+			}
+
+			var event twitch.RedemptionEvent
+			if err := json.Unmarshal(n.Event, &event); err != nil {
+				fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+				return
+			}
+
+			if err := rewardstats.Record(channelID, rewardstats.FromEvent(event, time.Now())); err != nil {
+				fmt.Printf("Failed to record redemption %s: %s\n", event.ID, err)
+				return
+			}
+			fmt.Printf("%s %s: %s (%s)\n", event.UserName, strings.ToLower(event.Status), event.Reward.Title, event.ID)
+		})
+	},
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/monktype/msc/callback"
+	"github.com/monktype/msc/pollscript"
+	"github.com/monktype/msc/twitch"
 	"github.com/spf13/cobra"
 )
 
@@ -50,23 +53,93 @@ func init() {
 	pollCmd.MarkFlagRequired("title")
 	pollCmd.Flags().IntP("duration", "d", 0, "Duration in seconds")
 	pollCmd.MarkFlagRequired("duration")
+	pollCmd.Flags().Bool("truncate", false, "Shorten a title over 60 characters or options over 25 characters instead of refusing the poll")
+	pollCmd.Flags().Int("channel-points-vote", 0, "Let viewers buy extra votes for this many channel points each (0 is off)")
 	pollCmd.Flags().BoolP("no-watch", "n", false, "Skip watching until the end of the poll, just print the poll ID.")
 	pollCmd.Flags().BoolP("send-announcement", "a", false, "Send an announcement when the poll starts. 'New poll for X seconds: \"Poll Title\"'")
 	pollCmd.Flags().BoolP("send-announcement-result", "A", false, "Send an announcement when the poll starts AND when the poll ends with the result. Implies -a.")
+	pollCmd.Flags().String("announcement-text", defaultPollAnnouncement, "Template for the start announcement; {poll_title}, {duration}, and the announcement variables are filled in")
+	pollCmd.Flags().String("result-announcement-text", defaultPollResultAnnouncement, "Template for the result announcement; {poll_title}, {result}, and the announcement variables are filled in")
+	pollCmd.Flags().Bool("runoff", false, "On a tie, run runoff polls with only the tied options, then pick at random")
+	pollCmd.Flags().Int("runoff-rounds", 2, "Most runoff polls to run with --runoff before picking at random")
+	pollCmd.Flags().Int("runoff-duration", 30, "Duration in seconds for each runoff poll")
+	pollCmd.Flags().String("runoff-announcement-text", defaultPollRunoffAnnouncement, "Template for announcing a runoff poll; {poll_title}, {duration}, {tied}, and the announcement variables are filled in")
+	pollCmd.Flags().String("tie-break-announcement-text", defaultPollTieBreakAnnouncement, "Template for announcing a random tie-break; {poll_title}, {tied}, {winner}, {rounds}, and the announcement variables are filled in")
 	rootCmd.AddCommand(pollCmd)
+	pollListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	pollListCmd.MarkFlagRequired("channel-name")
+	pollCmd.AddCommand(pollListCmd)
+	pollGetCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	pollGetCmd.MarkFlagRequired("channel-name")
+	pollCmd.AddCommand(pollGetCmd)
+	pollEndCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	pollEndCmd.MarkFlagRequired("channel-name")
+	pollEndCmd.Flags().Bool("archive", false, "Archive the poll, hiding its results from the channel")
+	pollCmd.AddCommand(pollEndCmd)
+	pollWatchCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	pollWatchCmd.MarkFlagRequired("channel-name")
+	pollWatchCmd.Flags().BoolP("send-announcement-result", "A", false, "Send an announcement with the result when the poll ends")
+	pollWatchCmd.Flags().String("result-announcement-text", defaultPollResultAnnouncement, "Template for the result announcement; {poll_title}, {result}, and the announcement variables are filled in")
+	pollCmd.AddCommand(pollWatchCmd)
+	pollHistoryCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	pollHistoryCmd.MarkFlagRequired("channel-name")
+	pollHistoryCmd.Flags().String("since", "7d", "How far back to go (7d, 2w, 36h, or a date like 2006-01-02); empty for every poll Twitch still has")
+	pollHistoryCmd.Flags().StringP("format", "f", "", "Output format (csv, json, markdown); defaults by file extension, markdown otherwise")
+	pollCmd.AddCommand(pollHistoryCmd)
+	pollRunScriptCmd.Flags().StringP("channel-name", "c", "", "Target channel name (overrides the script's channel)")
+	pollRunScriptCmd.Flags().Duration("gap", pollscript.DefaultGap, "Wait between polls (overrides the script's gap)")
+	pollCmd.AddCommand(pollRunScriptCmd)
+	rootCmd.AddCommand(predictionCmd)
+	predictionCreateCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionCreateCmd.MarkFlagRequired("channel-name")
+	predictionCreateCmd.Flags().StringP("title", "t", "", "Title for prediction")
+	predictionCreateCmd.MarkFlagRequired("title")
+	predictionCreateCmd.Flags().IntP("duration", "d", 0, "Prediction window in seconds (30-1800)")
+	predictionCreateCmd.MarkFlagRequired("duration")
+	predictionCreateCmd.Flags().BoolP("no-watch", "n", false, "Skip watching until the prediction locks, just print the prediction ID.")
+	predictionCmd.AddCommand(predictionCreateCmd)
+	predictionListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionListCmd.MarkFlagRequired("channel-name")
+	predictionCmd.AddCommand(predictionListCmd)
+	predictionWatchCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionWatchCmd.MarkFlagRequired("channel-name")
+	predictionWatchCmd.Flags().StringP("id", "i", "", "Prediction ID")
+	predictionWatchCmd.MarkFlagRequired("id")
+	predictionCmd.AddCommand(predictionWatchCmd)
+	predictionLockCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionLockCmd.MarkFlagRequired("channel-name")
+	predictionLockCmd.Flags().StringP("id", "i", "", "Prediction ID")
+	predictionLockCmd.MarkFlagRequired("id")
+	predictionCmd.AddCommand(predictionLockCmd)
+	predictionResolveCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionResolveCmd.MarkFlagRequired("channel-name")
+	predictionResolveCmd.Flags().StringP("id", "i", "", "Prediction ID")
+	predictionResolveCmd.MarkFlagRequired("id")
+	predictionResolveCmd.Flags().StringP("winner", "w", "", "Winning outcome: its title, its number (1-10), or its ID")
+	predictionResolveCmd.MarkFlagRequired("winner")
+	predictionCmd.AddCommand(predictionResolveCmd)
+	predictionCancelCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	predictionCancelCmd.MarkFlagRequired("channel-name")
+	predictionCancelCmd.Flags().StringP("id", "i", "", "Prediction ID")
+	predictionCancelCmd.MarkFlagRequired("id")
+	predictionCmd.AddCommand(predictionCancelCmd)
 	announcementCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	announcementCmd.MarkFlagRequired("channel-name")
 	announcementCmd.Flags().StringP("border-color", "b", "primary", "Border color (primary, blue, green, orange, purple)")
+	announcementCmd.Flags().StringP("file", "f", "", "Read the announcement from a file; each line is its own announcement (\"-\" reads stdin)")
+	announcementCmd.Flags().DurationP("delay", "d", twitch.DefaultAnnouncementDelay, "Delay between announcements when there's more than one")
 	rootCmd.AddCommand(announcementCmd)
 	shoutoutCmd.Flags().StringP("channel-name", "c", "", "Channel name to send shoutout")
 	shoutoutCmd.MarkFlagRequired("channel-name")
 	shoutoutCmd.Flags().StringP("shoutout-name", "s", "", "Shoutout name")
-	shoutoutCmd.MarkFlagRequired("shoutout-name")
+	shoutoutCmd.Flags().BoolP("queue", "q", false, "Queue the shoutout(s) and send them as Twitch's cooldowns allow; extra arguments are more names")
+	shoutoutCmd.Flags().BoolP("no-wait", "n", false, "With --queue, only queue; don't wait to send")
 	rootCmd.AddCommand(shoutoutCmd)
 	startadCmd.Flags().StringP("channel-name", "c", "", "Channel name to start ads")
 	startadCmd.MarkFlagRequired("channel-name")
 	startadCmd.Flags().IntP("length", "l", 60, "Ad length in seconds (30, 60, 90, 120, 150, 180)")
 	startadCmd.MarkFlagRequired("length")
+	startadCmd.Flags().Bool("no-pause-rewards", false, "Don't pause the rewards set with reward ad-pause during this ad")
 	rootCmd.AddCommand(startadCmd)
 	rootCmd.AddCommand(emoteonlyCmd)
 	emoteonlyOnCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
@@ -106,6 +179,150 @@ func init() {
 	submodeOffCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	submodeOffCmd.MarkFlagRequired("channel-name")
 	submodeCmd.AddCommand(submodeOffCmd)
+	rootCmd.AddCommand(chatCmd)
+	chatDeleteCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	chatDeleteCmd.MarkFlagRequired("channel-name")
+	chatDeleteCmd.Flags().StringP("message-id", "m", "", "Message ID (UUID) to delete")
+	chatDeleteCmd.MarkFlagRequired("message-id")
+	chatCmd.AddCommand(chatDeleteCmd)
+	chatClearCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	chatClearCmd.MarkFlagRequired("channel-name")
+	chatClearCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	chatCmd.AddCommand(chatClearCmd)
+	rootCmd.AddCommand(blockedTermsCmd)
+	blockedTermsListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	blockedTermsListCmd.MarkFlagRequired("channel-name")
+	blockedTermsCmd.AddCommand(blockedTermsListCmd)
+	blockedTermsAddCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	blockedTermsAddCmd.MarkFlagRequired("channel-name")
+	blockedTermsCmd.AddCommand(blockedTermsAddCmd)
+	blockedTermsRemoveCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	blockedTermsRemoveCmd.MarkFlagRequired("channel-name")
+	blockedTermsCmd.AddCommand(blockedTermsRemoveCmd)
+	blockedTermsImportCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	blockedTermsImportCmd.MarkFlagRequired("channel-name")
+	blockedTermsImportCmd.Flags().BoolP("sync", "s", false, "Also remove blocked terms on the channel that aren't in the file")
+	blockedTermsImportCmd.Flags().BoolP("dry-run", "n", false, "Only show the changes, don't make them")
+	blockedTermsImportCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt before removing terms with --sync")
+	blockedTermsCmd.AddCommand(blockedTermsImportCmd)
+	blockedTermsExportCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	blockedTermsExportCmd.MarkFlagRequired("channel-name")
+	blockedTermsExportCmd.Flags().StringP("format", "f", "", "Output format (text, csv); defaults to csv for .csv files and text otherwise")
+	blockedTermsCmd.AddCommand(blockedTermsExportCmd)
+	rootCmd.AddCommand(automodCmd)
+	automodCmd.AddCommand(automodSettingsCmd)
+	automodSettingsShowCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	automodSettingsShowCmd.MarkFlagRequired("channel-name")
+	automodSettingsCmd.AddCommand(automodSettingsShowCmd)
+	automodSettingsSetCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	automodSettingsSetCmd.MarkFlagRequired("channel-name")
+	automodSettingsSetCmd.Flags().IntP("overall", "o", 0, "Overall level for every category (0..4)")
+	for _, category := range twitch.AutoModCategories {
+		automodSettingsSetCmd.Flags().Int(strings.ReplaceAll(category, "_", "-"), 0, fmt.Sprintf("Level for %s (0..4)", category))
+	}
+	automodSettingsCmd.AddCommand(automodSettingsSetCmd)
+	automodCheckCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	automodCheckCmd.MarkFlagRequired("channel-name")
+	automodCmd.AddCommand(automodCheckCmd)
+	automodQueueCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	automodQueueCmd.MarkFlagRequired("channel-name")
+	automodCmd.AddCommand(automodQueueCmd)
+	rootCmd.AddCommand(modsCmd)
+	modsListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	modsListCmd.MarkFlagRequired("channel-name")
+	modsListCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	modsCmd.AddCommand(modsListCmd)
+	modsAddCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	modsAddCmd.MarkFlagRequired("channel-name")
+	modsCmd.AddCommand(modsAddCmd)
+	modsRemoveCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	modsRemoveCmd.MarkFlagRequired("channel-name")
+	modsCmd.AddCommand(modsRemoveCmd)
+	rootCmd.AddCommand(vipsCmd)
+	vipsListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	vipsListCmd.MarkFlagRequired("channel-name")
+	vipsListCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	vipsCmd.AddCommand(vipsListCmd)
+	vipsAddCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	vipsAddCmd.MarkFlagRequired("channel-name")
+	vipsCmd.AddCommand(vipsAddCmd)
+	vipsRemoveCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	vipsRemoveCmd.MarkFlagRequired("channel-name")
+	vipsCmd.AddCommand(vipsRemoveCmd)
+	rootCmd.AddCommand(rosterCmd)
+	rosterApplyCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	rosterApplyCmd.MarkFlagRequired("channel-name")
+	rosterApplyCmd.Flags().BoolP("dry-run", "n", false, "Only show the changes, don't make them")
+	rosterApplyCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	rosterApplyCmd.Flags().Bool("allow-empty", false, "Apply a file with no moderators or no VIPs, removing all of them")
+	rosterCmd.AddCommand(rosterApplyCmd)
+	rootCmd.AddCommand(shieldCmd)
+	shieldOnCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	shieldOnCmd.MarkFlagRequired("channel-name")
+	shieldCmd.AddCommand(shieldOnCmd)
+	shieldOffCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	shieldOffCmd.MarkFlagRequired("channel-name")
+	shieldCmd.AddCommand(shieldOffCmd)
+	shieldStatusCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	shieldStatusCmd.MarkFlagRequired("channel-name")
+	shieldCmd.AddCommand(shieldStatusCmd)
+	warnCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	warnCmd.MarkFlagRequired("channel-name")
+	warnCmd.Flags().StringP("user", "u", "", "Username to warn")
+	warnCmd.MarkFlagRequired("user")
+	warnCmd.Flags().StringP("reason", "r", "", "Reason shown to the user (max 500 characters)")
+	warnCmd.MarkFlagRequired("reason")
+	rootCmd.AddCommand(warnCmd)
+	rootCmd.AddCommand(unbanRequestsCmd)
+	unbanRequestsListCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	unbanRequestsListCmd.MarkFlagRequired("channel-name")
+	unbanRequestsListCmd.Flags().StringP("status", "s", "pending", "Request status (pending, approved, denied, acknowledged, canceled)")
+	unbanRequestsCmd.AddCommand(unbanRequestsListCmd)
+	unbanRequestsApproveCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	unbanRequestsApproveCmd.MarkFlagRequired("channel-name")
+	unbanRequestsApproveCmd.Flags().StringP("request", "i", "", "Unban request ID")
+	unbanRequestsApproveCmd.MarkFlagRequired("request")
+	unbanRequestsApproveCmd.Flags().StringP("resolution", "r", "", "Resolution text shown to the user")
+	unbanRequestsCmd.AddCommand(unbanRequestsApproveCmd)
+	unbanRequestsDenyCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	unbanRequestsDenyCmd.MarkFlagRequired("channel-name")
+	unbanRequestsDenyCmd.Flags().StringP("request", "i", "", "Unban request ID")
+	unbanRequestsDenyCmd.MarkFlagRequired("request")
+	unbanRequestsDenyCmd.Flags().StringP("resolution", "r", "", "Resolution text shown to the user")
+	unbanRequestsCmd.AddCommand(unbanRequestsDenyCmd)
+	rootCmd.AddCommand(lockdownCmd)
+	lockdownOnCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	lockdownOnCmd.MarkFlagRequired("channel-name")
+	lockdownOnCmd.Flags().StringP("profile", "p", "", "YAML lockdown profile (default: Shield Mode, emote-only, followers-only 30m, slow mode 30s)")
+	lockdownCmd.AddCommand(lockdownOnCmd)
+	lockdownOffCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	lockdownOffCmd.MarkFlagRequired("channel-name")
+	lockdownCmd.AddCommand(lockdownOffCmd)
+	lockdownStatusCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	lockdownStatusCmd.MarkFlagRequired("channel-name")
+	lockdownCmd.AddCommand(lockdownStatusCmd)
+	rootCmd.AddCommand(raidCmd)
+	raidStartCmd.Flags().StringP("channel-name", "c", "", "Channel name to raid from")
+	raidStartCmd.MarkFlagRequired("channel-name")
+	raidStartCmd.Flags().StringP("target", "t", "", "Channel name to raid")
+	raidStartCmd.MarkFlagRequired("target")
+	raidStartCmd.Flags().StringP("announce", "a", "", "Send an announcement before the raid; {target}, {target_game}, {target_title}, {target_viewers}, and the announcement variables are filled in")
+	raidStartCmd.Flags().Lookup("announce").NoOptDefVal = defaultRaidAnnouncement
+	raidStartCmd.Flags().StringP("border-color", "b", "primary", "Announcement border color (primary, blue, green, orange, purple)")
+	raidStartCmd.Flags().BoolP("force", "f", false, "Raid even if the target isn't live")
+	raidCmd.AddCommand(raidStartCmd)
+	raidCancelCmd.Flags().StringP("channel-name", "c", "", "Channel name with the pending raid")
+	raidCancelCmd.MarkFlagRequired("channel-name")
+	raidCmd.AddCommand(raidCancelCmd)
+	rootCmd.AddCommand(timersCmd)
+	timersRunCmd.Flags().StringP("channel-name", "c", "", "Target channel name (overrides the config file's channel)")
+	timersCmd.AddCommand(timersRunCmd)
+	timersCmd.AddCommand(timersListCmd)
+	timersCmd.AddCommand(timersPauseCmd)
+	timersCmd.AddCommand(timersResumeCmd)
+	rootCmd.AddCommand(redemptionsCmd)
+	redemptionsServeCmd.Flags().StringP("channel-name", "c", "", "Target channel name (overrides the config file's channel)")
+	redemptionsCmd.AddCommand(redemptionsServeCmd)
 	rootCmd.AddCommand(rewardsCmd)
 	rewardscreateCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	rewardscreateCmd.MarkFlagRequired("channel-name")
@@ -127,25 +344,78 @@ func init() {
 	rewardsCmd.AddCommand(rewardsgetCmd)
 	rewardsredemptionsCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	rewardsredemptionsCmd.MarkFlagRequired("channel-name")
-	rewardsredemptionsCmd.Flags().StringP("reward", "r", "", "Reward ID (UUID)")
-	rewardsredemptionsCmd.MarkFlagRequired("reward")
+	rewardsredemptionsCmd.Flags().StringP("reward", "r", "", "Reward ID (UUID). Optional with --all, which then lists every reward msc created")
 	rewardsredemptionsCmd.Flags().StringP("status", "s", "", "Redemption status. Can only be CANCELED, FULFILLED, and UNFULFILLED")
 	rewardsredemptionsCmd.MarkFlagRequired("status")
+	rewardsredemptionsCmd.Flags().Bool("all", false, "Page through every redemption instead of only the first 50")
+	rewardsredemptionsCmd.Flags().String("sort", "OLDEST", "Order to list redemptions in: OLDEST or NEWEST")
 	rewardsCmd.AddCommand(rewardsredemptionsCmd)
 	rewardscancelCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	rewardscancelCmd.MarkFlagRequired("channel-name")
 	rewardscancelCmd.Flags().StringP("reward", "r", "", "Reward ID (UUID). This is the button on the Twitch UI, not the redeemed reward instance.")
-	rewardscancelCmd.MarkFlagRequired("reward")
 	rewardscancelCmd.Flags().StringP("redemption", "i", "", "Redemption ID (UUID). This is the redeemed reward instance.")
-	rewardscancelCmd.MarkFlagRequired("redemption")
+	rewardscancelCmd.Flags().Bool("all", false, "Cancel every unfulfilled redemption matching the filters below, on -r or on every reward msc created")
+	rewardscancelCmd.Flags().String("older-than", "", "With --all, only redemptions older than this (e.g. 2h, 3d, 1w, or 2006-01-02)")
+	rewardscancelCmd.Flags().String("user", "", "With --all, only redemptions by this user (login name)")
+	rewardscancelCmd.Flags().String("input-match", "", "With --all, only redemptions whose user input matches this regular expression")
+	rewardscancelCmd.Flags().BoolP("dry-run", "n", false, "With --all, only show what matches, don't change anything")
+	rewardscancelCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
 	rewardsCmd.AddCommand(rewardscancelCmd)
 	rewardsfulfillCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
 	rewardsfulfillCmd.MarkFlagRequired("channel-name")
 	rewardsfulfillCmd.Flags().StringP("reward", "r", "", "Reward ID (UUID). This is the button on the Twitch UI, not the redeemed reward instance.")
-	rewardsfulfillCmd.MarkFlagRequired("reward")
 	rewardsfulfillCmd.Flags().StringP("redemption", "i", "", "Redemption ID (UUID). This is the redeemed reward instance.")
-	rewardsfulfillCmd.MarkFlagRequired("redemption")
+	rewardsfulfillCmd.Flags().Bool("all", false, "Fulfill every unfulfilled redemption matching the filters below, on -r or on every reward msc created")
+	rewardsfulfillCmd.Flags().String("older-than", "", "With --all, only redemptions older than this (e.g. 2h, 3d, 1w, or 2006-01-02)")
+	rewardsfulfillCmd.Flags().String("user", "", "With --all, only redemptions by this user (login name)")
+	rewardsfulfillCmd.Flags().String("input-match", "", "With --all, only redemptions whose user input matches this regular expression")
+	rewardsfulfillCmd.Flags().BoolP("dry-run", "n", false, "With --all, only show what matches, don't change anything")
+	rewardsfulfillCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
 	rewardsCmd.AddCommand(rewardsfulfillCmd)
+	for _, adpauseCmd := range []*cobra.Command{rewardsadpauseAddCmd, rewardsadpauseRemoveCmd, rewardsadpauseListCmd, rewardsadpauseRestoreCmd, rewardsadpauseWatchCmd} {
+		adpauseCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+		adpauseCmd.MarkFlagRequired("channel-name")
+		rewardsadpauseCmd.AddCommand(adpauseCmd)
+	}
+	rewardsCmd.AddCommand(rewardsadpauseCmd)
+	rewardsstatsCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	rewardsstatsCmd.MarkFlagRequired("channel-name")
+	rewardsstatsCmd.Flags().String("since", "7d", "Start of the window (7d, 2w, 36h, or a date like 2006-01-02); all for everything recorded")
+	rewardsstatsCmd.Flags().String("until", "", "End of the window, like --since; empty for now")
+	rewardsstatsCmd.Flags().Int("top", 10, "How many top redeemers to list; 0 for all")
+	rewardsstatsCmd.Flags().StringP("format", "f", "", "Output format (table, csv, json); defaults by file extension, table otherwise")
+	for _, statsCmd := range []*cobra.Command{rewardsstatsBackfillCmd, rewardsstatsRecordCmd} {
+		statsCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+		statsCmd.MarkFlagRequired("channel-name")
+		rewardsstatsCmd.AddCommand(statsCmd)
+	}
+	rewardsCmd.AddCommand(rewardsstatsCmd)
+	rewardsupdateCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	rewardsupdateCmd.MarkFlagRequired("channel-name")
+	rewardsupdateCmd.Flags().StringP("reward", "r", "", "Reward ID (UUID)")
+	rewardsupdateCmd.MarkFlagRequired("reward")
+	rewardsupdateCmd.Flags().StringP("title", "t", "", "New reward title")
+	rewardsupdateCmd.Flags().IntP("points", "p", 0, "New point cost")
+	rewardsupdateCmd.Flags().StringP("user-prompt", "u", "", "New prompt (shows if input is enabled)")
+	rewardsupdateCmd.Flags().StringP("background-color", "b", "", "New background color in hex notation (ie #9147FF)")
+	rewardsupdateCmd.Flags().Bool("enabled", false, "Enable or disable (--enabled=false) the reward")
+	rewardsupdateCmd.Flags().Bool("paused", false, "Pause or unpause (--paused=false) the reward")
+	rewardsupdateCmd.Flags().BoolP("input-required", "i", false, "Require user input or not (--input-required=false)")
+	rewardsupdateCmd.Flags().Int("max-per-stream", 0, "Most redemptions per stream (0 turns the limit off)")
+	rewardsupdateCmd.Flags().Int("max-per-user-per-stream", 0, "Most redemptions per user per stream (0 turns the limit off)")
+	rewardsupdateCmd.Flags().Int("global-cooldown", 0, "Seconds between redemptions (0 turns the cooldown off)")
+	rewardsupdateCmd.Flags().Bool("skip-queue", false, "Mark redemptions fulfilled right away or not (--skip-queue=false)")
+	rewardsCmd.AddCommand(rewardsupdateCmd)
+	rewardsapplyCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	rewardsapplyCmd.MarkFlagRequired("channel-name")
+	rewardsapplyCmd.Flags().BoolP("dry-run", "n", false, "Only show the plan, don't make the changes")
+	rewardsapplyCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	rewardsapplyCmd.Flags().Bool("prune", false, "Delete rewards created by msc that aren't in the file")
+	rewardsapplyCmd.Flags().Bool("disable-instead-of-delete", false, "With --prune, disable those rewards instead of deleting them")
+	rewardsCmd.AddCommand(rewardsapplyCmd)
+	rewardsexportCmd.Flags().StringP("channel-name", "c", "", "Target channel name")
+	rewardsexportCmd.MarkFlagRequired("channel-name")
+	rewardsCmd.AddCommand(rewardsexportCmd)
 	startApiCmd.Flags().IntP("port", "p", 8080, "Port for API server")
 	rootCmd.AddCommand(startApiCmd)
 }
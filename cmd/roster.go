@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+	"github.com/spf13/cobra"
+)
+
+// rosterMember is a moderator or VIP in the shape both list commands print.
+type rosterMember struct {
+	UserID    string `json:"user_id"`
+	UserLogin string `json:"user_login"`
+	UserName  string `json:"user_name"`
+}
+
+// rosterFile is the YAML file for `roster apply`: the complete list of who should be a moderator or VIP.
+type rosterFile struct {
+	Moderators []string `yaml:"moderators"`
+	VIPs       []string `yaml:"vips"`
+}
+
+var modsCmd = &cobra.Command{
+	Use:   "mods",
+	Short: "List, add, or remove moderators with -c (channel name) flag",
+}
+
+var modsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all moderators on the channel",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterList(cmd, false)
+	},
+}
+
+var modsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Make users moderators; each argument is a username",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterChange(cmd, args, "Added moderator", twitch.AddModerator)
+	},
+}
+
+var modsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove moderators; each argument is a username",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterChange(cmd, args, "Removed moderator", twitch.RemoveModerator)
+	},
+}
+
+var vipsCmd = &cobra.Command{
+	Use:   "vips",
+	Short: "List, add, or remove VIPs with -c (channel name) flag",
+}
+
+var vipsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all VIPs on the channel",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterList(cmd, true)
+	},
+}
+
+var vipsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Make users VIPs; each argument is a username",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterChange(cmd, args, "Added VIP", twitch.AddVIP)
+	},
+}
+
+var vipsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove VIPs; each argument is a username",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rosterChange(cmd, args, "Removed VIP", twitch.RemoveVIP)
+	},
+}
+
+var rosterCmd = &cobra.Command{
+	Use:   "roster",
+	Short: "Manage moderators and VIPs together from a file",
+}
+
+var rosterApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make the channel's moderators and VIPs match a YAML file with -c (channel name), followed by the file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		dryrun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		allowempty, err := cmd.Flags().GetBool("allow-empty")
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Printf("Failed to read %s: %s\n", args[0], err)
+			return err
+		}
+
+		// Strict, so a misspelled key is an error instead of an empty list that removes everyone.
+		var roster rosterFile
+		if err := yaml.UnmarshalWithOptions(data, &roster, yaml.Strict()); err != nil {
+			fmt.Printf("Failed to parse %s: %s\n", args[0], err)
+			return err
+		}
+
+		if !allowempty {
+			lists := []struct {
+				kind string
+				list []string
+			}{{"moderators", roster.Moderators}, {"vips", roster.VIPs}}
+			for _, l := range lists {
+				if len(l.list) == 0 {
+					fmt.Printf("%s has no %s, which would remove every one on the channel. Use --allow-empty if that's what you want.\n", args[0], l.kind)
+					return fmt.Errorf("refusing to apply an empty %s list", l.kind)
+				}
+			}
+		}
+
+		for _, mod := range roster.Moderators {
+			for _, vip := range roster.VIPs {
+				if strings.EqualFold(mod, vip) {
+					fmt.Printf("%s is listed as both a moderator and a VIP; Twitch only allows one.\n", mod)
+					return fmt.Errorf("fix the roster file")
+				}
+			}
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		wantMods, err := resolveLogins(c, roster.Moderators)
+		if err != nil {
+			return err
+		}
+
+		wantVIPs, err := resolveLogins(c, roster.VIPs)
+		if err != nil {
+			return err
+		}
+
+		mods, err := twitch.GetModerators(c, channelID)
+		if err != nil {
+			return err
+		}
+		currentMods := make(map[string]string)
+		for _, mod := range mods {
+			currentMods[mod.UserID] = mod.UserLogin
+		}
+
+		vips, err := twitch.GetVIPs(c, channelID)
+		if err != nil {
+			return err
+		}
+		currentVIPs := make(map[string]string)
+		for _, vip := range vips {
+			currentVIPs[vip.UserID] = vip.UserLogin
+		}
+
+		addMods, removeMods := diffRoster(currentMods, wantMods)
+		addVIPs, removeVIPs := diffRoster(currentVIPs, wantVIPs)
+
+		if len(addMods)+len(removeMods)+len(addVIPs)+len(removeVIPs) == 0 {
+			fmt.Printf("Roster already matches %s.\n", args[0])
+			return nil
+		}
+
+		printRosterDiff("moderator", addMods, removeMods, currentMods, wantMods)
+		printRosterDiff("VIP", addVIPs, removeVIPs, currentVIPs, wantVIPs)
+
+		if dryrun {
+			fmt.Printf("\nDry run; nothing was changed.\n")
+			return nil
+		}
+
+		if !yes && !askConfirmation("\nApply these changes?") {
+			fmt.Printf("Not applying roster.\n")
+			return nil
+		}
+
+		// Removals go first so that a VIP being promoted (or a moderator being demoted to VIP) isn't refused by Twitch.
+		for _, id := range removeMods {
+			if err := twitch.RemoveModerator(c, channelID, id); err != nil {
+				return err
+			}
+			fmt.Printf("Removed moderator %s\n", currentMods[id])
+		}
+		for _, id := range removeVIPs {
+			if err := twitch.RemoveVIP(c, channelID, id); err != nil {
+				return err
+			}
+			fmt.Printf("Removed VIP %s\n", currentVIPs[id])
+		}
+		for _, id := range addMods {
+			if err := twitch.AddModerator(c, channelID, id); err != nil {
+				return err
+			}
+			fmt.Printf("Added moderator %s\n", wantMods[id])
+		}
+		for _, id := range addVIPs {
+			if err := twitch.AddVIP(c, channelID, id); err != nil {
+				return err
+			}
+			fmt.Printf("Added VIP %s\n", wantVIPs[id])
+		}
+
+		return nil
+	},
+}
+
+// rosterList prints the channel's moderators (or VIPs) as text or JSON, depending on --output.
+func rosterList(cmd *cobra.Command, vips bool) error {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return err
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	if output != "text" && output != "json" {
+		fmt.Printf("Output can only be text or json.\n")
+		return fmt.Errorf("output string is incorrect")
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return err
+	}
+
+	members := []rosterMember{}
+	kind := "moderators"
+	if vips {
+		kind = "VIPs"
+		list, err := twitch.GetVIPs(c, channelID)
+		if err != nil {
+			return err
+		}
+		for _, vip := range list {
+			members = append(members, rosterMember{UserID: vip.UserID, UserLogin: vip.UserLogin, UserName: vip.UserName})
+		}
+	} else {
+		list, err := twitch.GetModerators(c, channelID)
+		if err != nil {
+			return err
+		}
+		for _, mod := range list {
+			members = append(members, rosterMember{UserID: mod.UserID, UserLogin: mod.UserLogin, UserName: mod.UserName})
+		}
+	}
+
+	if output == "json" {
+		out, err := json.MarshalIndent(members, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+		return nil
+	}
+
+	if len(members) != 0 {
+		fmt.Printf("Current %s on channel (%d):\n\n", kind, len(members))
+
+		for _, member := range members {
+			fmt.Printf("%s:\t%s\n", member.UserID, member.UserName)
+		}
+
+		fmt.Printf("\n")
+	} else {
+		fmt.Printf("No %s currently found.\n", kind)
+	}
+
+	return nil
+}
+
+// rosterChange runs change (add or remove, moderator or VIP) for every username in args.
+func rosterChange(cmd *cobra.Command, args []string, done string, change func(helix.Client, string, string) error) error {
+	if len(args) < 1 {
+		fmt.Printf("At least 1 username is required.\n")
+		return fmt.Errorf("give at least one username")
+	}
+
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return err
+	}
+
+	for _, username := range args {
+		targetID, err := twitch.GetUserID(c, username)
+		if err != nil {
+			return err
+		}
+
+		err = change(c, channelID, targetID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s %s\n", done, username)
+	}
+
+	return nil
+}
+
+// resolveLogins turns usernames into a map of user ID -> username.
+func resolveLogins(c helix.Client, logins []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	for _, login := range logins {
+		id, err := twitch.GetUserID(c, login)
+		if err != nil {
+			return ids, err
+		}
+		ids[id] = strings.ToLower(login)
+	}
+
+	return ids, nil
+}
+
+// diffRoster returns the user IDs to add and to remove to get from current to want, each sorted for stable output.
+func diffRoster(current map[string]string, want map[string]string) ([]string, []string) {
+	var add, remove []string
+
+	for id := range want {
+		if _, ok := current[id]; !ok {
+			add = append(add, id)
+		}
+	}
+	for id := range current {
+		if _, ok := want[id]; !ok {
+			remove = append(remove, id)
+		}
+	}
+
+	sort.Slice(add, func(i, j int) bool { return want[add[i]] < want[add[j]] })
+	sort.Slice(remove, func(i, j int) bool { return current[remove[i]] < current[remove[j]] })
+
+	return add, remove
+}
+
+func printRosterDiff(kind string, add []string, remove []string, current map[string]string, want map[string]string) {
+	for _, id := range add {
+		fmt.Printf("+ %s %s\n", kind, want[id])
+	}
+	for _, id := range remove {
+		fmt.Printf("- %s %s\n", kind, current[id])
+	}
+}
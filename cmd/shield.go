@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var shieldCmd = &cobra.Command{
+	Use:   "shield",
+	Short: "Turn Shield Mode on or off, or show its status, with -c (channel name) flag",
+}
+
+var shieldOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Turn on Shield Mode with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setShieldMode(cmd, true)
+	},
+}
+
+var shieldOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Turn off Shield Mode with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setShieldMode(cmd, false)
+	},
+}
+
+var shieldStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show Shield Mode status with -c (channel name) flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		status, err := twitch.GetShieldModeStatus(c, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		printShieldModeStatus(status)
+		return nil
+	},
+}
+
+func setShieldMode(cmd *cobra.Command, state bool) error {
+	channelname, err := cmd.Flags().GetString("channel-name")
+	if err != nil {
+		return err
+	}
+
+	c, err := twitch.GetClient()
+	if err != nil {
+		return err
+	}
+
+	channelID, err := twitch.GetUserID(c, channelname)
+	if err != nil {
+		return err
+	}
+
+	userID, err := twitch.GetMyUserID(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := twitch.UpdateShieldModeStatus(c, userID, channelID, state)
+	if err != nil {
+		return err
+	}
+
+	printShieldModeStatus(status)
+	return nil
+}
+
+func printShieldModeStatus(status twitch.ShieldModeStatus) {
+	if status.IsActive {
+		fmt.Printf("Shield Mode is ON.\n")
+	} else {
+		fmt.Printf("Shield Mode is OFF.\n")
+	}
+
+	if status.LastActivatedAt.IsZero() {
+		fmt.Printf("It has never been activated.\n")
+		return
+	}
+
+	fmt.Printf("Last activated by %s at %s (%s ago).\n",
+		status.ModeratorName,
+		status.LastActivatedAt.Local().Format("2006-01-02 15:04:05"),
+		time.Since(status.LastActivatedAt.Time).Round(time.Second))
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monktype/msc/timers"
+	"github.com/monktype/msc/twitch"
+	"github.com/spf13/cobra"
+)
+
+var timersCmd = &cobra.Command{
+	Use:   "timers",
+	Short: "Run, list, pause, or resume recurring chat timers from a config file",
+}
+
+var timersRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the timers in a YAML config file, optionally with -c (channel name), until CTRL+C",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelname, err := cmd.Flags().GetString("channel-name")
+		if err != nil {
+			return err
+		}
+
+		config, err := timers.LoadConfig(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		if channelname == "" {
+			channelname = config.Channel
+		}
+		if channelname == "" {
+			fmt.Printf("Give a channel with -c or a channel in %s.\n", args[0])
+			return fmt.Errorf("no channel given")
+		}
+
+		c, err := twitch.GetClient()
+		if err != nil {
+			return err
+		}
+
+		channelID, err := twitch.GetUserID(c, channelname)
+		if err != nil {
+			return err
+		}
+
+		userID, err := twitch.GetMyUserID(c)
+		if err != nil {
+			return err
+		}
+
+		runner, err := timers.NewRunner(c, config, userID, channelID)
+		if err != nil {
+			return err
+		}
+
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		fmt.Printf("Running %d timers on %s. Timers only fire while the channel is live.\n", len(config.Timers), channelname)
+		fmt.Printf("Press CTRL+C to stop.\n\n")
+
+		return runner.Run(stop)
+	},
+}
+
+var timersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the timers in a YAML config file with their state from the running (or last) `timers run`",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := timers.LoadConfig(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return err
+		}
+
+		status, err := timers.GetStatus()
+		if err != nil {
+			return err
+		}
+
+		paused, err := timers.GetPauseState()
+		if err != nil {
+			return err
+		}
+
+		running := make(map[string]timers.Status)
+		for _, timer := range status.Timers {
+			running[timer.Name] = timer
+		}
+
+		fmt.Printf("Timers in %s (%d):\n\n", args[0], len(config.Timers))
+
+		for _, timer := range config.Timers {
+			state := "enabled"
+			if !timer.IsEnabled() {
+				state = "disabled"
+			} else if paused.IsPaused(timer.Name) {
+				state = "paused"
+			}
+
+			fmt.Printf("%s (%s, %s): every %s", timer.Name, timer.Type, state, timer.Interval)
+			if timer.Jitter > 0 {
+				fmt.Printf(" ± %s", timer.Jitter)
+			}
+			if timer.MinMessages > 0 {
+				fmt.Printf(", after %d chat messages", timer.MinMessages)
+			}
+			fmt.Printf(", %d messages\n", len(timer.Messages))
+
+			if last, ok := running[timer.Name]; ok && timer.IsEnabled() {
+				if !last.LastFired.IsZero() {
+					fmt.Printf("\tLast fired %s\n", last.LastFired.Local().Format("2006-01-02 15:04:05"))
+				}
+				fmt.Printf("\tNext due %s, %d chat messages since last firing\n", last.NextFire.Local().Format("2006-01-02 15:04:05"), last.MessagesSinceFire)
+			}
+		}
+
+		fmt.Printf("\n")
+		if status.UpdatedAt.IsZero() {
+			fmt.Printf("`msc timers run` hasn't run yet.\n")
+		} else if time.Since(status.UpdatedAt) > time.Minute {
+			fmt.Printf("`msc timers run` last ran %s.\n", status.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+		} else if !status.Live {
+			fmt.Printf("`msc timers run` is running; the channel is offline, so timers are waiting.\n")
+		}
+
+		return nil
+	},
+}
+
+var timersPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause a timer by name, or every timer with no name",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		if err := timers.Pause(name); err != nil {
+			return err
+		}
+
+		if name == "" {
+			fmt.Printf("Paused all timers.\n")
+		} else {
+			fmt.Printf("Paused timer %s.\n", name)
+		}
+		return nil
+	},
+}
+
+var timersResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a timer by name, or every timer with no name",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		if err := timers.Resume(name); err != nil {
+			return err
+		}
+
+		if name == "" {
+			fmt.Printf("Resumed all timers.\n")
+		} else {
+			fmt.Printf("Resumed timer %s.\n", name)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,218 @@
+package eventsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// This is the Twitch EventSub WebSocket endpoint; it can be changed for testing against the Twitch CLI's mock server.
+var WebSocketURL = "wss://eventsub.wss.twitch.tv/ws"
+
+// Twitch only redelivers a message within this long of first sending it, so older message IDs needn't be kept.
+const redeliveryWindow = 10 * time.Minute
+
+// Notification is a single event delivered over the session.
+// Event is left raw so the caller can unmarshal it into the struct for the subscription type.
+type Notification struct {
+	Type    string
+	Version string
+	Event   json.RawMessage
+}
+
+// Session is one EventSub WebSocket connection.
+// ID is what subscriptions are created against (see twitch.SubscribeEventSub).
+type Session struct {
+	ID        string
+	conn      *websocket.Conn
+	keepalive time.Duration
+	closed    bool
+	lock      sync.Mutex
+}
+
+type messageMetadata struct {
+	MessageID           string `json:"message_id"`
+	MessageType         string `json:"message_type"`
+	SubscriptionType    string `json:"subscription_type"`
+	SubscriptionVersion string `json:"subscription_version"`
+}
+
+type message struct {
+	Metadata messageMetadata `json:"metadata"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+type sessionPayload struct {
+	Session struct {
+		ID                      string `json:"id"`
+		Status                  string `json:"status"`
+		KeepaliveTimeoutSeconds int    `json:"keepalive_timeout_seconds"`
+		ReconnectURL            string `json:"reconnect_url"`
+	} `json:"session"`
+}
+
+type notificationPayload struct {
+	Subscription struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+		Status  string `json:"status"`
+	} `json:"subscription"`
+	Event json.RawMessage `json:"event"`
+}
+
+// Connect opens a new EventSub WebSocket session and waits for Twitch's welcome message.
+// Subscriptions have to be created with the returned session's ID within 10 seconds or Twitch closes the connection.
+// Returns *Session and error.
+func Connect() (*Session, error) {
+	conn, welcome, err := dial(WebSocketURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		ID:        welcome.Session.ID,
+		conn:      conn,
+		keepalive: time.Duration(welcome.Session.KeepaliveTimeoutSeconds) * time.Second,
+	}, nil
+}
+
+// dial connects to url and reads the session_welcome message that Twitch sends first.
+func dial(url string) (*websocket.Conn, sessionPayload, error) {
+	var welcome sessionPayload
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, welcome, fmt.Errorf("failed to connect to EventSub: %s", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+
+	var msg message
+	if err := conn.ReadJSON(&msg); err != nil {
+		conn.Close()
+		return nil, welcome, fmt.Errorf("failed to read EventSub welcome: %s", err)
+	}
+	if msg.Metadata.MessageType != "session_welcome" {
+		conn.Close()
+		return nil, welcome, fmt.Errorf("expected EventSub welcome, got %s", msg.Metadata.MessageType)
+	}
+	if err := json.Unmarshal(msg.Payload, &welcome); err != nil {
+		conn.Close()
+		return nil, welcome, fmt.Errorf("failed to decode EventSub welcome: %s", err)
+	}
+
+	return conn, welcome, nil
+}
+
+// Listen reads from the session and calls handler for every notification, in order, on the calling goroutine.
+// Reconnect requests from Twitch are followed without losing subscriptions.
+// It blocks until the connection fails, a keepalive is missed, or Close is called.
+// Returns nil after Close, otherwise the error that ended the session.
+func (s *Session) Listen(handler func(Notification)) error {
+	// Twitch may redeliver a notification; the IDs make it safe to skip repeats. Sessions can run for days, so IDs are
+	// forgotten once they're past the window Twitch redelivers in.
+	seen := make(map[string]time.Time)
+	lastSweep := time.Now()
+
+	for {
+		// Twitch sends a keepalive when there's nothing else to send, so silence past the timeout means the connection is gone.
+		if s.keepalive > 0 {
+			s.conn.SetReadDeadline(time.Now().Add(s.keepalive + 5*time.Second))
+		}
+
+		var msg message
+		err := s.conn.ReadJSON(&msg)
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			return fmt.Errorf("EventSub connection lost: %s", err)
+		}
+
+		if msg.Metadata.MessageType == "notification" && msg.Metadata.MessageID != "" {
+			now := time.Now()
+			if now.Sub(lastSweep) > redeliveryWindow {
+				for id, at := range seen {
+					if now.Sub(at) > redeliveryWindow {
+						delete(seen, id)
+					}
+				}
+				lastSweep = now
+			}
+
+			if _, ok := seen[msg.Metadata.MessageID]; ok {
+				continue
+			}
+			seen[msg.Metadata.MessageID] = now
+		}
+
+		switch msg.Metadata.MessageType {
+		case "session_keepalive":
+			continue
+		case "session_reconnect":
+			var reconnect sessionPayload
+			if err := json.Unmarshal(msg.Payload, &reconnect); err != nil {
+				return fmt.Errorf("failed to decode EventSub reconnect: %s", err)
+			}
+			if err := s.reconnect(reconnect.Session.ReconnectURL); err != nil {
+				return err
+			}
+		case "revocation":
+			var payload notificationPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err == nil {
+				fmt.Printf("EventSub subscription %s was revoked by Twitch: %s\n", payload.Subscription.Type, payload.Subscription.Status)
+			}
+		case "notification":
+			var payload notificationPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				fmt.Printf("Failed to decode EventSub notification: %s\n", err)
+				continue
+			}
+			handler(Notification{
+				Type:    payload.Subscription.Type,
+				Version: payload.Subscription.Version,
+				Event:   payload.Event,
+			})
+		}
+	}
+}
+
+// reconnect moves the session to the URL Twitch asked for; the old connection is closed once the new one is welcomed.
+func (s *Session) reconnect(url string) error {
+	conn, welcome, err := dial(url)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	old := s.conn
+	s.conn = conn
+	s.ID = welcome.Session.ID
+	if welcome.Session.KeepaliveTimeoutSeconds > 0 {
+		s.keepalive = time.Duration(welcome.Session.KeepaliveTimeoutSeconds) * time.Second
+	}
+	old.Close()
+
+	return nil
+}
+
+// Close ends the session, which makes Listen return nil.
+func (s *Session) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	return s.conn.Close()
+}
+
+func (s *Session) isClosed() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.closed
+}
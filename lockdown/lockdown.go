@@ -0,0 +1,228 @@
+package lockdown
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Profile is what a lockdown turns on. Followers-only and slow mode are left alone when they're nil.
+type Profile struct {
+	ShieldMode      bool `yaml:"shield_mode" json:"shield_mode"`
+	EmoteOnly       bool `yaml:"emote_only" json:"emote_only"`
+	SubscribersOnly bool `yaml:"subscribers_only" json:"subscribers_only"`
+	FollowersOnly   *int `yaml:"followers_only" json:"followers_only"` // Minutes a user has to have followed for; 0 is any follower
+	SlowMode        *int `yaml:"slow_mode" json:"slow_mode"`           // Seconds between messages
+}
+
+// Twitch's limits for the settings a profile can turn on.
+const (
+	SlowModeMin      = 3      // seconds
+	SlowModeMax      = 120    // seconds
+	FollowersOnlyMax = 129600 // minutes (90 days)
+)
+
+// Validate checks the profile against Twitch's limits, so a bad value is caught before anything is changed rather
+// than halfway through a lockdown.
+// Returns error.
+func (p Profile) Validate() error {
+	var problems []string
+
+	if p.FollowersOnly != nil && (*p.FollowersOnly < 0 || *p.FollowersOnly > FollowersOnlyMax) {
+		problems = append(problems, fmt.Sprintf("followers_only is %d, it has to be 0 to %d minutes", *p.FollowersOnly, FollowersOnlyMax))
+	}
+	if p.SlowMode != nil && (*p.SlowMode < SlowModeMin || *p.SlowMode > SlowModeMax) {
+		problems = append(problems, fmt.Sprintf("slow_mode is %d, it has to be %d to %d seconds", *p.SlowMode, SlowModeMin, SlowModeMax))
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("invalid lockdown profile: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// DefaultProfile is the lockdown used without a profile file: Shield Mode, followers-only for 30 minutes,
+// emote-only, and 30-second slow mode.
+func DefaultProfile() Profile {
+	followers := 30
+	slow := 30
+	return Profile{
+		ShieldMode:    true,
+		EmoteOnly:     true,
+		FollowersOnly: &followers,
+		SlowMode:      &slow,
+	}
+}
+
+// LoadProfile reads a lockdown profile from a YAML file; settings missing from the file are off (or left alone).
+// Returns Profile and error.
+func LoadProfile(path string) (Profile, error) {
+	var profile Profile
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile, err
+	}
+
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	return profile, profile.Validate()
+}
+
+// Snapshot is the channel's chat settings from before the lockdown, which Off puts back.
+type Snapshot struct {
+	ChannelID            string    `json:"channel_id"`
+	TakenAt              time.Time `json:"taken_at"`
+	ShieldMode           bool      `json:"shield_mode"`
+	EmoteMode            bool      `json:"emote_mode"`
+	SubscriberMode       bool      `json:"subscriber_mode"`
+	FollowerMode         bool      `json:"follower_mode"`
+	FollowerModeDuration int       `json:"follower_mode_duration"`
+	SlowMode             bool      `json:"slow_mode"`
+	SlowModeWaitTime     int       `json:"slow_mode_wait_time"`
+}
+
+func snapshotFile(channelID string) string {
+	return fmt.Sprintf("lockdown-%s.json", channelID)
+}
+
+// GetSnapshot returns the saved snapshot for a channel, if it's locked down.
+// Returns Snapshot, true if the channel is locked down, and error.
+func GetSnapshot(channelID string) (Snapshot, bool, error) {
+	var snapshot Snapshot
+	if err := store.Load(snapshotFile(channelID), &snapshot); err != nil {
+		return snapshot, false, err
+	}
+
+	return snapshot, snapshot.ChannelID != "", nil
+}
+
+// On saves the channel's current chat settings and Shield Mode state to disk, then applies the profile.
+// If the channel is already locked down, the first snapshot is kept (it's what Off should go back to) and
+// the profile is applied again.
+// Every step is tried even if an earlier one fails (a missing Shield Mode scope shouldn't leave chat wide open), and
+// the failures are reported together.
+// Returns the snapshot and error. If applying fails partway, the snapshot is still saved so Off can undo it.
+func On(c helix.Client, userID string, channelID string, profile Profile) (Snapshot, error) {
+	if err := profile.Validate(); err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, locked, err := GetSnapshot(channelID)
+	if err != nil {
+		return snapshot, err
+	}
+
+	if !locked {
+		settings, err := twitch.GetChatSettings(c, channelID)
+		if err != nil {
+			return snapshot, err
+		}
+
+		shield, err := twitch.GetShieldModeStatus(c, userID, channelID)
+		if err != nil {
+			return snapshot, err
+		}
+
+		snapshot = Snapshot{
+			ChannelID:            channelID,
+			TakenAt:              time.Now(),
+			ShieldMode:           shield.IsActive,
+			EmoteMode:            settings.EmoteMode,
+			SubscriberMode:       settings.SubscriberMode,
+			FollowerMode:         settings.FollowerMode,
+			FollowerModeDuration: settings.FollowerModeDuration,
+			SlowMode:             settings.SlowMode,
+			SlowModeWaitTime:     settings.SlowModeWaitTime,
+		}
+
+		if err := store.Save(snapshotFile(channelID), snapshot); err != nil {
+			return snapshot, fmt.Errorf("failed to save chat settings snapshot, not locking down: %s", err)
+		}
+	}
+
+	var failed []string
+
+	// Shield Mode goes first; it does the most the fastest.
+	if profile.ShieldMode {
+		if _, err := twitch.UpdateShieldModeStatus(c, userID, channelID, true); err != nil {
+			failed = append(failed, fmt.Sprintf("shield mode: %s", err))
+		}
+	}
+	if profile.EmoteOnly {
+		if err := twitch.EmoteOnly(c, userID, channelID, true); err != nil {
+			failed = append(failed, fmt.Sprintf("emote-only: %s", err))
+		}
+	}
+	if profile.SubscribersOnly {
+		if err := twitch.SubOnlyMode(c, userID, channelID, true); err != nil {
+			failed = append(failed, fmt.Sprintf("subscribers-only: %s", err))
+		}
+	}
+	if profile.FollowersOnly != nil {
+		if err := twitch.FollowerOnlyDuration(c, userID, channelID, *profile.FollowersOnly); err != nil {
+			failed = append(failed, fmt.Sprintf("followers-only: %s", err))
+		}
+	}
+	if profile.SlowMode != nil {
+		if err := twitch.SlowmodeDuration(c, userID, channelID, *profile.SlowMode); err != nil {
+			failed = append(failed, fmt.Sprintf("slow mode: %s", err))
+		}
+	}
+
+	if len(failed) != 0 {
+		return snapshot, fmt.Errorf("lockdown only partly applied: %s", strings.Join(failed, "; "))
+	}
+
+	return snapshot, nil
+}
+
+// Off puts the channel's chat settings and Shield Mode back the way the snapshot has them, then removes the snapshot.
+// Returns the restored snapshot and error; the snapshot is kept if anything fails so Off can be tried again.
+func Off(c helix.Client, userID string, channelID string) (Snapshot, error) {
+	snapshot, locked, err := GetSnapshot(channelID)
+	if err != nil {
+		return snapshot, err
+	}
+	if !locked {
+		return snapshot, fmt.Errorf("channel isn't locked down; there's no snapshot to restore")
+	}
+
+	if err := twitch.EmoteOnly(c, userID, channelID, snapshot.EmoteMode); err != nil {
+		return snapshot, err
+	}
+	if err := twitch.SubOnlyMode(c, userID, channelID, snapshot.SubscriberMode); err != nil {
+		return snapshot, err
+	}
+	if snapshot.FollowerMode {
+		err = twitch.FollowerOnlyDuration(c, userID, channelID, snapshot.FollowerModeDuration)
+	} else {
+		err = twitch.FollowerOnly(c, userID, channelID, false)
+	}
+	if err != nil {
+		return snapshot, err
+	}
+	if snapshot.SlowMode {
+		err = twitch.SlowmodeDuration(c, userID, channelID, snapshot.SlowModeWaitTime)
+	} else {
+		err = twitch.Slowmode(c, userID, channelID, false)
+	}
+	if err != nil {
+		return snapshot, err
+	}
+	// Shield Mode goes last, so chat stays protected until everything else is back.
+	if _, err := twitch.UpdateShieldModeStatus(c, userID, channelID, snapshot.ShieldMode); err != nil {
+		return snapshot, err
+	}
+
+	return snapshot, store.Remove(snapshotFile(channelID))
+}
@@ -0,0 +1,98 @@
+package pollscript
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/monktype/msc/twitch"
+)
+
+// DefaultGap is the wait between polls when the script doesn't give one.
+const DefaultGap = 10 * time.Second
+
+// DefaultSummary is the announcement at the end of a script that doesn't give one.
+const DefaultSummary = "That's the end! Chat picked the right answer {correct_count} out of {scored} times."
+
+// Poll is one poll in a script.
+// Before and After are announcement templates; both are optional.
+type Poll struct {
+	Title                string        `yaml:"title" json:"title"`
+	Options              []string      `yaml:"options" json:"options"`
+	Duration             time.Duration `yaml:"duration" json:"duration"`
+	Correct              string        `yaml:"correct" json:"correct"` // Optional; one of the options
+	Before               string        `yaml:"before" json:"before"`   // Announced before the poll starts
+	After                string        `yaml:"after" json:"after"`     // Announced after the poll ends
+	ChannelPointsPerVote int           `yaml:"channel_points_per_vote" json:"channel_points_per_vote"`
+}
+
+// Script is the script file: polls to run one after another.
+type Script struct {
+	Channel string        `yaml:"channel"` // Optional; the -c flag wins
+	Gap     time.Duration `yaml:"gap"`     // Wait between polls; defaults to DefaultGap
+	Summary string        `yaml:"summary"` // Announcement at the end; defaults to DefaultSummary
+	Polls   []Poll        `yaml:"polls"`
+}
+
+// Seconds is the poll's duration in seconds, as Twitch takes it.
+func (p Poll) Seconds() int {
+	return int(p.Duration / time.Second)
+}
+
+// LoadScript reads and checks a script file. Every poll is checked against Twitch's limits before anything runs.
+// Returns Script and error.
+func LoadScript(path string) (Script, error) {
+	var script Script
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return script, err
+	}
+
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return script, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	if len(script.Polls) == 0 {
+		return script, fmt.Errorf("%s has no polls", path)
+	}
+
+	if script.Gap == 0 {
+		script.Gap = DefaultGap
+	}
+	if script.Gap < 0 {
+		return script, fmt.Errorf("gap can't be negative")
+	}
+	if script.Summary == "" {
+		script.Summary = DefaultSummary
+	}
+
+	for i := range script.Polls {
+		p := &script.Polls[i]
+
+		if p.Duration%time.Second != 0 {
+			return script, fmt.Errorf("poll %d (%s): duration must be whole seconds", i+1, p.Title)
+		}
+
+		if err := twitch.ValidatePoll(p.Title, p.Seconds(), p.Options, p.ChannelPointsPerVote); err != nil {
+			return script, fmt.Errorf("poll %d (%s): %s", i+1, p.Title, err)
+		}
+
+		if p.Correct != "" {
+			found := false
+			for _, option := range p.Options {
+				if strings.EqualFold(option, p.Correct) {
+					p.Correct = option // Scoring compares with the option as Twitch shows it.
+					found = true
+				}
+			}
+			if !found {
+				return script, fmt.Errorf("poll %d (%s): correct answer %s isn't one of the options", i+1, p.Title, p.Correct)
+			}
+		}
+	}
+
+	return script, nil
+}
@@ -0,0 +1,164 @@
+package pollscript
+
+import (
+	"time"
+
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// The runner writes the status file; pausing, resuming, and skipping (from the keyboard or the API server) write the
+// control file.
+const (
+	statusFile  = "pollscript-status.json"
+	controlFile = "pollscript-control.json"
+)
+
+// Outcomes of a poll in the scoreboard.
+const (
+	OutcomeCorrect  = "correct"  // The correct answer won outright
+	OutcomeWrong    = "wrong"    // Something else won
+	OutcomeTie      = "tie"      // Tied, so it doesn't count as right
+	OutcomeUnscored = "unscored" // The poll had no correct answer
+	OutcomeSkipped  = "skipped"
+	OutcomeFailed   = "failed" // The poll couldn't be started or watched
+)
+
+// Result is one poll's line in the scoreboard.
+type Result struct {
+	Title      string   `json:"title"`
+	Winners    []string `json:"winners"`
+	Correct    string   `json:"correct,omitempty"`
+	Outcome    string   `json:"outcome"`
+	TotalVotes int      `json:"total_votes"`
+}
+
+// Status is what the runner last reported.
+type Status struct {
+	ChannelID    string    `json:"channel_id"`
+	State        string    `json:"state"`   // waiting, running, paused, or done
+	Current      int       `json:"current"` // 1-based number of the poll running or coming up
+	Total        int       `json:"total"`
+	CurrentTitle string    `json:"current_title"`
+	Results      []Result  `json:"results"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Control is how a running script is steered from outside.
+type Control struct {
+	Paused bool `json:"paused"`
+	Skip   bool `json:"skip"` // Set until the runner skips the current or next poll
+}
+
+// Score works out a finished poll's outcome against its correct answer, with the winners from PollWinners.
+func Score(p Poll, poll helix.Poll) Result {
+	_, winners := twitch.PollWinners(poll)
+
+	result := Result{
+		Title:   p.Title,
+		Winners: winners,
+		Correct: p.Correct,
+	}
+	for _, choice := range poll.Choices {
+		result.TotalVotes += choice.Votes
+	}
+
+	switch {
+	case p.Correct == "":
+		result.Outcome = OutcomeUnscored
+	case len(winners) > 1:
+		result.Outcome = OutcomeTie
+	case len(winners) == 1 && winners[0] == p.Correct:
+		result.Outcome = OutcomeCorrect
+	default:
+		result.Outcome = OutcomeWrong
+	}
+
+	return result
+}
+
+// Tally counts the scoreboard: polls the correct answer won, polls that had a correct answer and ran, and skipped polls.
+func Tally(results []Result) (correct int, scored int, skipped int) {
+	for _, result := range results {
+		switch result.Outcome {
+		case OutcomeCorrect:
+			correct++
+			scored++
+		case OutcomeWrong, OutcomeTie:
+			scored++
+		case OutcomeSkipped:
+			skipped++
+		}
+	}
+	return correct, scored, skipped
+}
+
+// GetStatus reads what the runner last reported. UpdatedAt is zero if no script has run.
+// Returns Status and error.
+func GetStatus() (Status, error) {
+	var status Status
+	err := store.Load(statusFile, &status)
+	return status, err
+}
+
+// SaveStatus writes the runner's status.
+func SaveStatus(status Status) error {
+	status.UpdatedAt = time.Now()
+	return store.Save(statusFile, status)
+}
+
+// GetControl reads whether the script is paused or a skip is waiting.
+// Returns Control and error.
+func GetControl() (Control, error) {
+	var control Control
+	err := store.Load(controlFile, &control)
+	return control, err
+}
+
+// ResetControl clears a pause or skip left over from an earlier script.
+func ResetControl() error {
+	return store.Save(controlFile, Control{})
+}
+
+// Pause stops the running script from starting another poll; a poll that's running still finishes.
+// Returns error.
+func Pause() error {
+	return updateControl(func(control *Control) { control.Paused = true })
+}
+
+// Resume lets a paused script carry on.
+// Returns error.
+func Resume() error {
+	return updateControl(func(control *Control) { control.Paused = false })
+}
+
+// Skip ends the running poll without scoring it, or skips the next poll if none is running.
+// Returns error.
+func Skip() error {
+	return updateControl(func(control *Control) { control.Skip = true })
+}
+
+// TakeSkip reports whether a skip is waiting and clears it, so each skip is acted on once.
+// Returns bool and error.
+func TakeSkip() (bool, error) {
+	control, err := GetControl()
+	if err != nil || !control.Skip {
+		return false, err // Nothing to clear, so don't rewrite the file.
+	}
+
+	skip := false
+	err = updateControl(func(control *Control) {
+		skip = control.Skip
+		control.Skip = false
+	})
+	return skip, err
+}
+
+func updateControl(change func(control *Control)) error {
+	var control Control
+	return store.Update(controlFile, &control, func() error {
+		change(&control)
+		return nil
+	})
+}
@@ -0,0 +1,185 @@
+package redemptions
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/monktype/msc/twitch"
+)
+
+// DefaultTimeout is how long a command or webhook gets when the action doesn't say.
+const DefaultTimeout = 30 * time.Second
+
+// ActionTypes are the kinds of action a redemption can run.
+var ActionTypes = []string{"command", "webhook", "announcement", "chat"}
+
+// Action is what to do when a reward is redeemed.
+// Messages are templates: {user}, {input}, {reward}, {cost}, and the announcement variables are filled in.
+// Commands get the same values as MSC_* environment variables instead; see runCommand.
+type Action struct {
+	Reward  string        `yaml:"reward" json:"reward"`   // Reward ID or title
+	Type    string        `yaml:"type" json:"type"`       // command, webhook, announcement, or chat
+	Command string        `yaml:"command" json:"command"` // command: run with sh -c; on Windows, run directly (see commandArgs)
+	URL     string        `yaml:"url" json:"url"`         // webhook: gets the redemption POSTed as JSON
+	Message string        `yaml:"message" json:"message"` // announcement and chat
+	Color   string        `yaml:"color" json:"color"`     // announcement border color; defaults to primary
+	Timeout time.Duration `yaml:"timeout" json:"timeout"` // command and webhook; defaults to DefaultTimeout
+}
+
+// Config is the redemption processor config file.
+type Config struct {
+	Channel string   `yaml:"channel"` // Optional; the -c flag wins
+	Actions []Action `yaml:"actions"`
+}
+
+// AnnouncementColor returns the action's announcement color.
+func (a Action) AnnouncementColor() (twitch.AnnouncementColor, error) {
+	if a.Color == "" {
+		return twitch.AnnouncementColorPrimary, nil
+	}
+
+	for color, name := range twitch.AnnouncementColorMap {
+		if strings.EqualFold(a.Color, name) {
+			return color, nil
+		}
+	}
+
+	return twitch.AnnouncementColorPrimary, fmt.Errorf("action for %s: color %s is invalid; use primary, blue, green, orange, or purple", a.Reward, a.Color)
+}
+
+// LoadConfig reads and checks a redemption processor config file.
+// Returns Config and error.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	if len(config.Actions) == 0 {
+		return config, fmt.Errorf("%s has no actions", path)
+	}
+
+	rewards := make(map[string]bool)
+	for i := range config.Actions {
+		a := &config.Actions[i]
+
+		if a.Reward == "" {
+			return config, fmt.Errorf("action %d has no reward", i+1)
+		}
+		if rewards[strings.ToLower(a.Reward)] {
+			return config, fmt.Errorf("reward %s has more than one action", a.Reward)
+		}
+		rewards[strings.ToLower(a.Reward)] = true
+
+		if a.Timeout == 0 {
+			a.Timeout = DefaultTimeout
+		}
+		if a.Timeout < 0 {
+			return config, fmt.Errorf("action for %s: timeout can't be negative", a.Reward)
+		}
+
+		switch a.Type {
+		case "command":
+			if a.Command == "" {
+				return config, fmt.Errorf("action for %s: command actions need a command", a.Reward)
+			}
+			if runtime.GOOS == "windows" {
+				if _, err := commandArgs(a.Command); err != nil {
+					return config, fmt.Errorf("action for %s: %s", a.Reward, err)
+				}
+			}
+		case "webhook":
+			parsed, err := url.Parse(a.URL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return config, fmt.Errorf("action for %s: webhook actions need an http or https url", a.Reward)
+			}
+		case "announcement", "chat":
+			if a.Message == "" {
+				return config, fmt.Errorf("action for %s: %s actions need a message", a.Reward, a.Type)
+			}
+			if _, err := a.AnnouncementColor(); err != nil {
+				return config, err
+			}
+		default:
+			return config, fmt.Errorf("action for %s: type can only be %s", a.Reward, strings.Join(ActionTypes, ", "))
+		}
+	}
+
+	return config, nil
+}
+
+// commandArgs splits a command action into the program and its arguments for Windows, where it's run without a shell.
+// cmd expands %MSC_INPUT% before it parses the line, so quoting can't keep viewer input from adding commands there.
+// Arguments are separated by spaces, and double quotes keep spaces in one. Nothing else is special, so backslashes in
+// paths are kept. cmd itself and batch files (which Windows always runs through cmd) are refused.
+// Returns []string and error.
+func commandArgs(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+
+	for _, r := range command {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("the command has an unclosed quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command actions need a command")
+	}
+
+	program := strings.ToLower(filepath.Base(args[0]))
+	switch {
+	case program == "cmd" || program == "cmd.exe":
+		return nil, fmt.Errorf("commands can't run through cmd on Windows, since it puts viewer input into the command line")
+	case strings.HasSuffix(program, ".bat") || strings.HasSuffix(program, ".cmd"):
+		return nil, fmt.Errorf("batch files can't be commands on Windows, since they run through cmd, which puts viewer input into the command line; use a program or a PowerShell script (powershell -File script.ps1)")
+	}
+
+	return args, nil
+}
+
+// Find returns the action for a reward, matching its ID first and then its title (ignoring case).
+// Returns Action and whether there is one.
+func (c Config) Find(rewardID string, rewardTitle string) (Action, bool) {
+	for _, a := range c.Actions {
+		if a.Reward == rewardID {
+			return a, true
+		}
+	}
+	for _, a := range c.Actions {
+		if strings.EqualFold(a.Reward, rewardTitle) {
+			return a, true
+		}
+	}
+
+	return Action{}, false
+}
@@ -0,0 +1,300 @@
+package redemptions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monktype/msc/eventsub"
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Every outcome is added to this log in the msc state directory, one JSON object per line.
+const logFile = "redemptions-log.jsonl"
+
+// Outcome is what happened to one redemption.
+type Outcome struct {
+	Time         time.Time `json:"time"`
+	RedemptionID string    `json:"redemption_id"`
+	RewardID     string    `json:"reward_id"`
+	RewardTitle  string    `json:"reward_title"`
+	UserID       string    `json:"user_id"`
+	UserLogin    string    `json:"user_login"`
+	Input        string    `json:"input,omitempty"`
+	Action       string    `json:"action,omitempty"` // The action type; empty when no action matched
+	Status       string    `json:"status"`           // FULFILLED, CANCELED, IGNORED (no action), or the status it arrived with
+	Error        string    `json:"error,omitempty"`
+}
+
+// Processor runs the configured action for each new redemption and marks it fulfilled or canceled (refunded).
+type Processor struct {
+	config    Config
+	userID    string
+	channelID string
+	session   *eventsub.Session
+	running   sync.WaitGroup
+}
+
+// NewProcessor subscribes to the channel's new redemptions over EventSub.
+// Returns *Processor and error.
+func NewProcessor(c helix.Client, config Config, userID string, channelID string) (*Processor, error) {
+	session, err := eventsub.Connect()
+	if err != nil {
+		fmt.Printf("Failed to start EventSub session: %s\n", err)
+		return nil, err
+	}
+
+	err = twitch.SubscribeRedemptionEvents(c, session.ID, channelID)
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	return &Processor{
+		config:    config,
+		userID:    userID,
+		channelID: channelID,
+		session:   session,
+	}, nil
+}
+
+// Run processes redemptions until stop is closed or the EventSub connection fails.
+// Actions that are still running are waited for before it returns.
+// Returns nil when stopped, otherwise the error that ended it.
+func (p *Processor) Run(stop <-chan struct{}) error {
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- p.session.Listen(p.handleNotification)
+	}()
+
+	var err error
+	select {
+	case <-stop:
+		p.session.Close()
+	case err = <-listenErr:
+	}
+
+	p.running.Wait()
+	return err
+}
+
+func (p *Processor) handleNotification(n eventsub.Notification) {
+	if n.Type != "channel.channel_points_custom_reward_redemption.add" {
+		return
+	}
+
+	var event twitch.RedemptionEvent
+	if err := json.Unmarshal(n.Event, &event); err != nil {
+		fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+		return
+	}
+
+	// Actions can take a while (commands, webhooks), and Listen has to keep reading to stay connected.
+	p.running.Add(1)
+	go func() {
+		defer p.running.Done()
+		p.process(event)
+	}()
+}
+
+// process runs a redemption's action, marks the redemption, and logs the outcome.
+func (p *Processor) process(event twitch.RedemptionEvent) {
+	outcome := Outcome{
+		Time:         time.Now(),
+		RedemptionID: event.ID,
+		RewardID:     event.Reward.ID,
+		RewardTitle:  event.Reward.Title,
+		UserID:       event.UserID,
+		UserLogin:    event.UserLogin,
+		Input:        event.UserInput,
+	}
+
+	action, ok := p.config.Find(event.Reward.ID, event.Reward.Title)
+	if !ok {
+		outcome.Status = "IGNORED"
+		p.log(outcome)
+		return
+	}
+	outcome.Action = action.Type
+
+	// Get a fresh client in case the processor has been going long enough for the token to need a refresh.
+	c, err := twitch.GetClient()
+	if err != nil {
+		outcome.Status = strings.ToUpper(event.Status)
+		outcome.Error = err.Error()
+		p.log(outcome)
+		return
+	}
+
+	actionErr := p.run(c, action, event)
+
+	// Rewards that skip the request queue arrive already fulfilled, and there's nothing to refund.
+	if event.Status != "unfulfilled" {
+		outcome.Status = strings.ToUpper(event.Status)
+		if actionErr != nil {
+			outcome.Error = actionErr.Error()
+		}
+		p.log(outcome)
+		return
+	}
+
+	if actionErr == nil {
+		outcome.Status = "FULFILLED"
+		_, err = twitch.FulfillRedemption(c, p.channelID, event.Reward.ID, event.ID)
+	} else {
+		outcome.Status = "CANCELED"
+		outcome.Error = actionErr.Error()
+		_, err = twitch.CancelRedemption(c, p.channelID, event.Reward.ID, event.ID)
+	}
+	if err != nil {
+		// Twitch only lets msc mark redemptions of rewards msc created.
+		markErr := fmt.Sprintf("marking %s failed: %s", outcome.Status, err)
+		if outcome.Error != "" {
+			outcome.Error += "; " + markErr
+		} else {
+			outcome.Error = markErr
+		}
+		outcome.Status = "UNFULFILLED"
+	}
+
+	p.log(outcome)
+}
+
+// run runs one action for a redemption.
+// Returns error if the action failed.
+func (p *Processor) run(c helix.Client, action Action, event twitch.RedemptionEvent) error {
+	values := map[string]string{
+		"user":   event.UserName,
+		"input":  event.UserInput,
+		"reward": event.Reward.Title,
+		"cost":   fmt.Sprintf("%d", event.Reward.Cost),
+	}
+
+	switch action.Type {
+	case "command":
+		return runCommand(action, event)
+	case "webhook":
+		return postWebhook(action, event)
+	case "announcement":
+		color, err := action.AnnouncementColor()
+		if err != nil {
+			return err
+		}
+		announcements, err := twitch.RenderAnnouncements(c, p.channelID, action.Message, values)
+		if err != nil {
+			return err
+		}
+		return twitch.SendAnnouncements(c, p.userID, p.channelID, color, announcements, twitch.DefaultAnnouncementDelay)
+	case "chat":
+		message, err := twitch.RenderTemplate(c, p.channelID, action.Message, values)
+		if err != nil {
+			return err
+		}
+		for _, part := range twitch.SplitMessage(message, twitch.AnnouncementMaxLength) {
+			if err := twitch.SendChatMessage(c, p.userID, p.channelID, part); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown action type %s", action.Type)
+}
+
+// runCommand runs a command action with sh -c, or on Windows directly without a shell (see commandArgs). The redemption
+// is passed in MSC_* environment variables, never in the command line; with sh the command has to quote them
+// ("$MSC_INPUT") so viewer input can't change what runs.
+func runCommand(action Action, event twitch.RedemptionEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), action.Timeout)
+	defer cancel()
+
+	var command *exec.Cmd
+	if runtime.GOOS == "windows" {
+		args, err := commandArgs(action.Command)
+		if err != nil {
+			return err
+		}
+		command = exec.CommandContext(ctx, args[0], args[1:]...)
+	} else {
+		command = exec.CommandContext(ctx, "sh", "-c", action.Command)
+	}
+	command.Env = append(os.Environ(),
+		"MSC_REDEMPTION_ID="+event.ID,
+		"MSC_REWARD_ID="+event.Reward.ID,
+		"MSC_REWARD="+event.Reward.Title,
+		"MSC_COST="+fmt.Sprintf("%d", event.Reward.Cost),
+		"MSC_USER="+event.UserName,
+		"MSC_USER_LOGIN="+event.UserLogin,
+		"MSC_USER_ID="+event.UserID,
+		"MSC_INPUT="+event.UserInput,
+	)
+
+	output, err := command.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out after %s", action.Timeout)
+	}
+	if err != nil {
+		lastLine := strings.TrimSpace(string(output))
+		if i := strings.LastIndex(lastLine, "\n"); i >= 0 {
+			lastLine = lastLine[i+1:]
+		}
+		if lastLine != "" {
+			return fmt.Errorf("command failed: %s (%s)", err, lastLine)
+		}
+		return fmt.Errorf("command failed: %s", err)
+	}
+
+	return nil
+}
+
+// postWebhook POSTs the redemption as JSON to a webhook action's URL. Any 2xx response is success.
+func postWebhook(action Action, event twitch.RedemptionEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	client := http.Client{Timeout: action.Timeout}
+	resp, err := client.Post(action.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// log prints an outcome and adds it to the log file.
+func (p *Processor) log(outcome Outcome) {
+	line := fmt.Sprintf("[%s] %s redeemed %s: %s", outcome.Time.Format("15:04:05"), outcome.UserLogin, outcome.RewardTitle, outcome.Status)
+	if outcome.Action != "" {
+		line += fmt.Sprintf(" (%s)", outcome.Action)
+	}
+	if outcome.Error != "" {
+		line += fmt.Sprintf(": %s", outcome.Error)
+	}
+	fmt.Printf("%s\n", line)
+
+	if err := store.Append(logFile, outcome); err != nil {
+		fmt.Printf("Failed to write the redemption log: %s\n", err)
+	}
+}
+
+// LogPath returns where outcomes are logged.
+func LogPath() (string, error) {
+	return store.Path(logFile)
+}
@@ -0,0 +1,222 @@
+package rewardset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Kinds of Action.
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionDisable  = "disable"
+	ActionConflict = "conflict" // A reward with the title exists but msc didn't create it, so it can't be changed
+)
+
+// Action is one step of a plan.
+type Action struct {
+	Kind     string              `json:"kind"`
+	Title    string              `json:"title"`
+	RewardID string              `json:"reward_id,omitempty"` // The existing reward, for everything but create
+	Changes  []string            `json:"changes,omitempty"`   // What an update changes, for showing
+	Desired  Reward              `json:"-"`
+	Update   twitch.RewardUpdate `json:"-"`
+}
+
+// Plan works out what to do to get from the channel's rewards to the set.
+// Rewards in the set are matched to manageable rewards by ID first, then by title (ignoring case). Manageable rewards
+// that aren't in the set are deleted (or disabled) only if prune is set.
+// all is every reward on the channel, so titles taken by rewards msc can't manage show up as conflicts instead of
+// failing to create.
+// Deletes and disables come first so their titles are free, then updates, then creates.
+func Plan(set Set, all []helix.ChannelCustomReward, manageable []helix.ChannelCustomReward, prune bool, disableInstead bool) []Action {
+	var removals, updates, creates []Action
+
+	isManageable := make(map[string]bool)
+	for _, reward := range manageable {
+		isManageable[reward.ID] = true
+	}
+
+	// Every ID is matched before any title, so a reward claimed by ID later in the set isn't also taken by an earlier
+	// entry's title. Each reward is matched at most once.
+	matches := make([]*helix.ChannelCustomReward, len(set.Rewards))
+	matched := make(map[string]bool)
+	for i, desired := range set.Rewards {
+		if desired.ID == "" || matched[desired.ID] {
+			continue
+		}
+		for j := range manageable {
+			if manageable[j].ID == desired.ID {
+				matches[i] = &manageable[j]
+				matched[desired.ID] = true
+				break
+			}
+		}
+	}
+	for i, desired := range set.Rewards {
+		if matches[i] != nil {
+			continue
+		}
+		for j := range manageable {
+			if !matched[manageable[j].ID] && strings.EqualFold(manageable[j].Title, desired.Title) {
+				matches[i] = &manageable[j]
+				matched[manageable[j].ID] = true
+				break
+			}
+		}
+	}
+
+	for i, desired := range set.Rewards {
+		match := matches[i]
+		if match == nil {
+			conflict := ""
+			for _, reward := range all {
+				if !isManageable[reward.ID] && strings.EqualFold(reward.Title, desired.Title) {
+					conflict = reward.ID
+				}
+			}
+			if conflict != "" {
+				updates = append(updates, Action{Kind: ActionConflict, Title: desired.Title, RewardID: conflict, Desired: desired})
+			} else {
+				creates = append(creates, Action{Kind: ActionCreate, Title: desired.Title, Desired: desired})
+			}
+			continue
+		}
+
+		update, changes := diff(desired, *match)
+		if len(changes) != 0 {
+			updates = append(updates, Action{Kind: ActionUpdate, Title: match.Title, RewardID: match.ID, Changes: changes, Desired: desired, Update: update})
+		}
+	}
+
+	if prune {
+		for _, reward := range manageable {
+			if matched[reward.ID] {
+				continue
+			}
+			if !disableInstead {
+				removals = append(removals, Action{Kind: ActionDelete, Title: reward.Title, RewardID: reward.ID})
+			} else if reward.IsEnabled {
+				disabled := false
+				removals = append(removals, Action{Kind: ActionDisable, Title: reward.Title, RewardID: reward.ID, Update: twitch.RewardUpdate{IsEnabled: &disabled}})
+			}
+		}
+	}
+
+	return append(append(removals, updates...), creates...)
+}
+
+// diff works out the update that makes existing match desired, with a line describing each change.
+func diff(desired Reward, existing helix.ChannelCustomReward) (twitch.RewardUpdate, []string) {
+	var update twitch.RewardUpdate
+	var changes []string
+
+	if desired.Title != existing.Title {
+		update.Title = &desired.Title
+		changes = append(changes, fmt.Sprintf("title %q -> %q", existing.Title, desired.Title))
+	}
+	if desired.Cost != existing.Cost {
+		update.Cost = &desired.Cost
+		changes = append(changes, fmt.Sprintf("cost %d -> %d", existing.Cost, desired.Cost))
+	}
+	if desired.Prompt != existing.Prompt {
+		update.Prompt = &desired.Prompt
+		changes = append(changes, fmt.Sprintf("prompt %q -> %q", existing.Prompt, desired.Prompt))
+	}
+	if desired.BackgroundColor != "" && !strings.EqualFold(desired.BackgroundColor, existing.BackgroundColor) {
+		update.BackgroundColor = &desired.BackgroundColor
+		changes = append(changes, fmt.Sprintf("background color %s -> %s", existing.BackgroundColor, desired.BackgroundColor))
+	}
+
+	switches := []struct {
+		name  string
+		want  bool
+		have  bool
+		field **bool
+	}{
+		{"enabled", desired.IsEnabled(), existing.IsEnabled, &update.IsEnabled},
+		{"paused", desired.Paused, existing.IsPaused, &update.IsPaused},
+		{"input required", desired.InputRequired, existing.IsUserInputRequired, &update.IsUserInputRequired},
+		{"skip queue", desired.SkipQueue, existing.ShouldRedemptionsSkipRequestQueue, &update.ShouldRedemptionsSkipRequestQueue},
+	}
+	for _, s := range switches {
+		if s.want != s.have {
+			want := s.want
+			*s.field = &want
+			changes = append(changes, fmt.Sprintf("%s %t -> %t", s.name, s.have, s.want))
+		}
+	}
+
+	// Limits are a switch and a number on Twitch's side and just a number (0 is off) in the set.
+	limits := []struct {
+		name    string
+		want    int
+		have    int
+		enabled **bool
+		value   **int
+	}{
+		{"max per stream", desired.MaxPerStream, limitValue(existing.MaxPerStreamSetting.IsEnabled, existing.MaxPerStreamSetting.MaxPerStream), &update.IsMaxPerStreamEnabled, &update.MaxPerStream},
+		{"max per user per stream", desired.MaxPerUserPerStream, limitValue(existing.MaxPerUserPerStreamSetting.IsEnabled, existing.MaxPerUserPerStreamSetting.MaxPerUserPerStream), &update.IsMaxPerUserPerStreamEnabled, &update.MaxPerUserPerStream},
+		{"global cooldown seconds", int(desired.GlobalCooldown / time.Second), limitValue(existing.GlobalCooldownSetting.IsEnabled, existing.GlobalCooldownSetting.GlobalCooldownSeconds), &update.IsGlobalCooldownEnabled, &update.GlobalCooldownSeconds},
+	}
+	for _, l := range limits {
+		if l.want == l.have {
+			continue
+		}
+		enabled := l.want > 0
+		value := l.want
+		*l.enabled = &enabled
+		if enabled {
+			*l.value = &value
+		}
+		changes = append(changes, fmt.Sprintf("%s %s -> %s", l.name, limitString(l.have), limitString(l.want)))
+	}
+
+	return update, changes
+}
+
+func limitValue(enabled bool, value int) int {
+	if !enabled {
+		return 0
+	}
+	return value
+}
+
+func limitString(value int) string {
+	if value == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+// Apply carries out the action on the channel. Conflicts are skipped with an error.
+// Returns error.
+func (a Action) Apply(c helix.Client, channelID string) error {
+	switch a.Kind {
+	case ActionCreate:
+		rewardID, err := twitch.CreateReward(c, a.Desired.createParams(channelID))
+		if err != nil {
+			return err
+		}
+		if a.Desired.Paused {
+			paused := true
+			_, err = twitch.UpdateReward(c, channelID, rewardID, twitch.RewardUpdate{IsPaused: &paused})
+			return err
+		}
+		return nil
+	case ActionUpdate, ActionDisable:
+		_, err := twitch.UpdateReward(c, channelID, a.RewardID, a.Update)
+		return err
+	case ActionDelete:
+		return twitch.DeleteReward(c, channelID, a.RewardID)
+	case ActionConflict:
+		return fmt.Errorf("reward %s wasn't created by msc, so it can't be changed", a.Title)
+	}
+
+	return fmt.Errorf("unknown action %s", a.Kind)
+}
@@ -0,0 +1,273 @@
+package rewardset
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// existing is a channel reward that matches Reward{Title: title, Cost: cost} exactly.
+func existing(id string, title string, cost int) helix.ChannelCustomReward {
+	return helix.ChannelCustomReward{ID: id, Title: title, Cost: cost, IsEnabled: true}
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name           string
+		set            []Reward
+		all            []helix.ChannelCustomReward // Rewards msc didn't create; the manageable ones are added to all
+		manageable     []helix.ChannelCustomReward
+		prune          bool
+		disableInstead bool
+		expect         []string // kind:title:reward ID, in order
+	}{
+		{
+			name:   "empty",
+			expect: nil,
+		},
+		{
+			name:   "create",
+			set:    []Reward{{Title: "Hydrate", Cost: 100}},
+			expect: []string{"create:Hydrate:"},
+		},
+		{
+			name:       "already matches",
+			set:        []Reward{{Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100)},
+			expect:     nil,
+		},
+		{
+			name:       "matched by title ignoring case",
+			set:        []Reward{{Title: "Hydrate", Cost: 200}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "hydrate", 100)},
+			expect:     []string{"update:hydrate:r1"},
+		},
+		{
+			name:       "matched by ID before title",
+			set:        []Reward{{ID: "r2", Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100), existing("r2", "Stretch", 100)},
+			expect:     []string{"update:Stretch:r2"},
+		},
+		{
+			name:       "unknown ID falls back to title",
+			set:        []Reward{{ID: "gone", Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100)},
+			expect:     nil,
+		},
+		{
+			name:   "title taken by a reward msc didn't create",
+			set:    []Reward{{Title: "Hydrate", Cost: 100}},
+			all:    []helix.ChannelCustomReward{existing("x1", "HYDRATE", 50)},
+			expect: []string{"conflict:Hydrate:x1"},
+		},
+		{
+			name:       "extra rewards kept without prune",
+			set:        []Reward{{Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100), existing("r2", "Stretch", 100)},
+			expect:     nil,
+		},
+		{
+			name:       "prune deletes extra rewards",
+			set:        []Reward{{Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100), existing("r2", "Stretch", 100)},
+			prune:      true,
+			expect:     []string{"delete:Stretch:r2"},
+		},
+		{
+			name: "prune disables extra rewards that are enabled",
+			set:  []Reward{{Title: "Hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{
+				existing("r1", "Hydrate", 100),
+				existing("r2", "Stretch", 100),
+				{ID: "r3", Title: "Already off", Cost: 100},
+			},
+			prune:          true,
+			disableInstead: true,
+			expect:         []string{"disable:Stretch:r2"},
+		},
+		{
+			name:       "prune leaves rewards msc didn't create",
+			all:        []helix.ChannelCustomReward{existing("x1", "Theirs", 100)},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Ours", 100)},
+			prune:      true,
+			expect:     []string{"delete:Ours:r1"},
+		},
+		{
+			name: "removals, then updates and conflicts, then creates",
+			set: []Reward{
+				{Title: "New", Cost: 10},
+				{Title: "Hydrate", Cost: 200},
+				{Title: "Taken", Cost: 10},
+			},
+			all:        []helix.ChannelCustomReward{existing("x1", "Taken", 10)},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100), existing("r2", "Old", 100)},
+			prune:      true,
+			expect:     []string{"delete:Old:r2", "update:Hydrate:r1", "conflict:Taken:x1", "create:New:"},
+		},
+		{
+			name:       "ID match wins over an earlier title match",
+			set:        []Reward{{Title: "Hydrate", Cost: 100}, {ID: "r1", Title: "Stretch", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100)},
+			expect:     []string{"update:Hydrate:r1", "create:Hydrate:"},
+		},
+		{
+			name:       "an ID is only matched once",
+			set:        []Reward{{ID: "r1", Title: "Hydrate", Cost: 100}, {ID: "r1", Title: "Stretch", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100), existing("r2", "Stretch", 100)},
+			expect:     nil,
+		},
+		{
+			name:       "a reward is only matched once",
+			set:        []Reward{{Title: "Hydrate", Cost: 100}, {Title: "hydrate", Cost: 100}},
+			manageable: []helix.ChannelCustomReward{existing("r1", "Hydrate", 100)},
+			expect:     []string{"create:hydrate:"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			all := append(slices.Clone(test.all), test.manageable...)
+
+			var actions []string
+			for _, action := range Plan(Set{Rewards: test.set}, all, test.manageable, test.prune, test.disableInstead) {
+				actions = append(actions, action.Kind+":"+action.Title+":"+action.RewardID)
+			}
+			if !slices.Equal(actions, test.expect) {
+				t.Errorf("planned %q, expected %q", actions, test.expect)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	off := false
+
+	tests := []struct {
+		name     string
+		desired  Reward
+		existing helix.ChannelCustomReward
+		update   string // The update as JSON
+		changes  int
+	}{
+		{
+			name:     "no changes",
+			desired:  Reward{Title: "Hydrate", Cost: 100},
+			existing: existing("r1", "Hydrate", 100),
+			update:   `{}`,
+		},
+		{
+			name:     "title, cost and prompt",
+			desired:  Reward{Title: "Hydrate!", Cost: 150, Prompt: "Drink water"},
+			existing: existing("r1", "Hydrate", 100),
+			update:   `{"title":"Hydrate!","cost":150,"prompt":"Drink water"}`,
+			changes:  3,
+		},
+		{
+			name:     "background color ignores case",
+			desired:  Reward{Title: "Hydrate", Cost: 100, BackgroundColor: "#00ff00"},
+			existing: helix.ChannelCustomReward{Title: "Hydrate", Cost: 100, IsEnabled: true, BackgroundColor: "#00FF00"},
+			update:   `{}`,
+		},
+		{
+			name:     "empty background color leaves Twitch's",
+			desired:  Reward{Title: "Hydrate", Cost: 100},
+			existing: helix.ChannelCustomReward{Title: "Hydrate", Cost: 100, IsEnabled: true, BackgroundColor: "#00FF00"},
+			update:   `{}`,
+		},
+		{
+			name:     "background color changed",
+			desired:  Reward{Title: "Hydrate", Cost: 100, BackgroundColor: "#0000FF"},
+			existing: helix.ChannelCustomReward{Title: "Hydrate", Cost: 100, IsEnabled: true, BackgroundColor: "#00FF00"},
+			update:   `{"background_color":"#0000FF"}`,
+			changes:  1,
+		},
+		{
+			name:     "disabled",
+			desired:  Reward{Title: "Hydrate", Cost: 100, Enabled: &off},
+			existing: existing("r1", "Hydrate", 100),
+			update:   `{"is_enabled":false}`,
+			changes:  1,
+		},
+		{
+			name:     "enabled by default",
+			desired:  Reward{Title: "Hydrate", Cost: 100},
+			existing: helix.ChannelCustomReward{Title: "Hydrate", Cost: 100},
+			update:   `{"is_enabled":true}`,
+			changes:  1,
+		},
+		{
+			name:     "switches",
+			desired:  Reward{Title: "Hydrate", Cost: 100, Paused: true, InputRequired: true, SkipQueue: true},
+			existing: existing("r1", "Hydrate", 100),
+			update:   `{"is_paused":true,"is_user_input_required":true,"should_redemptions_skip_request_queue":true}`,
+			changes:  3,
+		},
+		{
+			name:     "limits turned on",
+			desired:  Reward{Title: "Hydrate", Cost: 100, MaxPerStream: 5, MaxPerUserPerStream: 1, GlobalCooldown: 90 * time.Second},
+			existing: existing("r1", "Hydrate", 100),
+			update:   `{"is_max_per_stream_enabled":true,"max_per_stream":5,"is_max_per_user_per_stream_enabled":true,"max_per_user_per_stream":1,"is_global_cooldown_enabled":true,"global_cooldown_seconds":90}`,
+			changes:  3,
+		},
+		{
+			name:    "limits turned off",
+			desired: Reward{Title: "Hydrate", Cost: 100},
+			existing: helix.ChannelCustomReward{
+				Title:                      "Hydrate",
+				Cost:                       100,
+				IsEnabled:                  true,
+				MaxPerStreamSetting:        helix.MaxPerStreamSettings{IsEnabled: true, MaxPerStream: 5},
+				MaxPerUserPerStreamSetting: helix.MaxPerUserPerStreamSettings{IsEnabled: true, MaxPerUserPerStream: 1},
+				GlobalCooldownSetting:      helix.GlobalCooldownSettings{IsEnabled: true, GlobalCooldownSeconds: 90},
+			},
+			update:  `{"is_max_per_stream_enabled":false,"is_max_per_user_per_stream_enabled":false,"is_global_cooldown_enabled":false}`,
+			changes: 3,
+		},
+		{
+			name:    "a limit that's switched off counts as off",
+			desired: Reward{Title: "Hydrate", Cost: 100},
+			existing: helix.ChannelCustomReward{
+				Title:               "Hydrate",
+				Cost:                100,
+				IsEnabled:           true,
+				MaxPerStreamSetting: helix.MaxPerStreamSettings{IsEnabled: false, MaxPerStream: 5},
+			},
+			update: `{}`,
+		},
+		{
+			name:    "limit changed",
+			desired: Reward{Title: "Hydrate", Cost: 100, GlobalCooldown: 2 * time.Minute},
+			existing: helix.ChannelCustomReward{
+				Title:                 "Hydrate",
+				Cost:                  100,
+				IsEnabled:             true,
+				GlobalCooldownSetting: helix.GlobalCooldownSettings{IsEnabled: true, GlobalCooldownSeconds: 60},
+			},
+			update:  `{"is_global_cooldown_enabled":true,"global_cooldown_seconds":120}`,
+			changes: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			update, changes := diff(test.desired, test.existing)
+
+			encoded, err := json.Marshal(update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(encoded) != test.update {
+				t.Errorf("update %s, expected %s", encoded, test.update)
+			}
+			if len(changes) != test.changes {
+				t.Errorf("%d changes (%q), expected %d", len(changes), changes, test.changes)
+			}
+			if update.IsEmpty() != (test.changes == 0) {
+				t.Errorf("update is empty: %t, with %d changes", update.IsEmpty(), len(changes))
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package rewardset
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Twitch's reward limits.
+const (
+	TitleMaxLength  = 45
+	PromptMaxLength = 200
+)
+
+// Reward is one reward in a reward set file, with every setting Twitch has.
+// Limits and the cooldown are off when they're 0.
+type Reward struct {
+	ID                  string        `yaml:"id,omitempty" json:"id,omitempty"` // Matched before the title; export fills it in
+	Title               string        `yaml:"title" json:"title"`
+	Cost                int           `yaml:"cost" json:"cost"`
+	Prompt              string        `yaml:"prompt,omitempty" json:"prompt,omitempty"`
+	BackgroundColor     string        `yaml:"background_color,omitempty" json:"background_color,omitempty"` // Left to Twitch when empty
+	Enabled             *bool         `yaml:"enabled,omitempty" json:"enabled,omitempty"`                   // Defaults to true
+	Paused              bool          `yaml:"paused,omitempty" json:"paused,omitempty"`
+	InputRequired       bool          `yaml:"input_required,omitempty" json:"input_required,omitempty"`
+	MaxPerStream        int           `yaml:"max_per_stream,omitempty" json:"max_per_stream,omitempty"`
+	MaxPerUserPerStream int           `yaml:"max_per_user_per_stream,omitempty" json:"max_per_user_per_stream,omitempty"`
+	GlobalCooldown      time.Duration `yaml:"global_cooldown,omitempty" json:"global_cooldown,omitempty"`
+	SkipQueue           bool          `yaml:"skip_queue,omitempty" json:"skip_queue,omitempty"`
+}
+
+// Set is a reward set file: the rewards a channel should have.
+type Set struct {
+	Rewards []Reward `yaml:"rewards" json:"rewards"`
+}
+
+// IsEnabled reports whether the reward should be enabled; rewards without an enabled field are.
+func (r Reward) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// LoadSet reads and checks a reward set file.
+// Returns Set and error.
+func LoadSet(path string) (Set, error) {
+	var set Set
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return set, err
+	}
+
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return set, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	titles := make(map[string]bool)
+	ids := make(map[string]bool)
+	for i, r := range set.Rewards {
+		if strings.TrimSpace(r.Title) == "" {
+			return set, fmt.Errorf("reward %d has no title", i+1)
+		}
+		if utf8.RuneCountInString(r.Title) > TitleMaxLength {
+			return set, fmt.Errorf("reward %s: the title can be at most %d characters", r.Title, TitleMaxLength)
+		}
+		if titles[strings.ToLower(r.Title)] {
+			return set, fmt.Errorf("reward title %s is used more than once", r.Title)
+		}
+		titles[strings.ToLower(r.Title)] = true
+
+		if r.ID != "" {
+			if ids[r.ID] {
+				return set, fmt.Errorf("reward ID %s is used more than once", r.ID)
+			}
+			ids[r.ID] = true
+		}
+
+		if r.Cost < 1 {
+			return set, fmt.Errorf("reward %s: the cost must be at least 1 point", r.Title)
+		}
+		if utf8.RuneCountInString(r.Prompt) > PromptMaxLength {
+			return set, fmt.Errorf("reward %s: the prompt can be at most %d characters", r.Title, PromptMaxLength)
+		}
+		if r.MaxPerStream < 0 || r.MaxPerUserPerStream < 0 {
+			return set, fmt.Errorf("reward %s: limits can't be negative", r.Title)
+		}
+		if r.GlobalCooldown < 0 || r.GlobalCooldown%time.Second != 0 {
+			return set, fmt.Errorf("reward %s: global_cooldown must be whole seconds", r.Title)
+		}
+	}
+
+	return set, nil
+}
+
+// FromReward turns a reward from Twitch into its reward set entry.
+func FromReward(reward helix.ChannelCustomReward) Reward {
+	enabled := reward.IsEnabled
+
+	r := Reward{
+		ID:              reward.ID,
+		Title:           reward.Title,
+		Cost:            reward.Cost,
+		Prompt:          reward.Prompt,
+		BackgroundColor: reward.BackgroundColor,
+		Enabled:         &enabled,
+		Paused:          reward.IsPaused,
+		InputRequired:   reward.IsUserInputRequired,
+		SkipQueue:       reward.ShouldRedemptionsSkipRequestQueue,
+	}
+	if reward.MaxPerStreamSetting.IsEnabled {
+		r.MaxPerStream = reward.MaxPerStreamSetting.MaxPerStream
+	}
+	if reward.MaxPerUserPerStreamSetting.IsEnabled {
+		r.MaxPerUserPerStream = reward.MaxPerUserPerStreamSetting.MaxPerUserPerStream
+	}
+	if reward.GlobalCooldownSetting.IsEnabled {
+		r.GlobalCooldown = time.Duration(reward.GlobalCooldownSetting.GlobalCooldownSeconds) * time.Second
+	}
+
+	return r
+}
+
+// WriteSet writes a reward set as YAML.
+// Returns error.
+func WriteSet(w io.Writer, set Set) error {
+	data, err := yaml.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// createParams is the reward as Twitch's create request. Twitch can't create a reward paused, so Apply pauses it after.
+func (r Reward) createParams(channelID string) helix.ChannelCustomRewardsParams {
+	return helix.ChannelCustomRewardsParams{
+		BroadcasterID:                     channelID,
+		Title:                             r.Title,
+		Cost:                              r.Cost,
+		Prompt:                            r.Prompt,
+		IsEnabled:                         r.IsEnabled(),
+		BackgroundColor:                   r.BackgroundColor,
+		IsUserInputRequired:               r.InputRequired,
+		IsMaxPerStreamEnabled:             r.MaxPerStream > 0,
+		MaxPerStream:                      r.MaxPerStream,
+		IsMaxPerUserPerStreamEnabled:      r.MaxPerUserPerStream > 0,
+		MaxPerUserPerStream:               r.MaxPerUserPerStream,
+		IsGlobalCooldownEnabled:           r.GlobalCooldown > 0,
+		GlobalCooldownSeconds:             int(r.GlobalCooldown / time.Second),
+		ShouldRedemptionsSkipRequestQueue: r.SkipQueue,
+	}
+}
@@ -0,0 +1,268 @@
+package rewardstats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/monktype/msc/twitch"
+)
+
+// Formats are the output formats WriteStats takes.
+var Formats = []string{"table", "csv", "json"}
+
+// Counts are the numbers kept for the whole window, each reward, and each redeemer.
+// The rates are of all redemptions, so unfulfilled ones still waiting count against both.
+type Counts struct {
+	Redemptions     int     `json:"redemptions"`
+	PointsSpent     int     `json:"points_spent"`
+	Fulfilled       int     `json:"fulfilled"`
+	Canceled        int     `json:"canceled"`
+	Unfulfilled     int     `json:"unfulfilled"`
+	FulfillmentRate float64 `json:"fulfillment_rate"`
+	RefundRate      float64 `json:"refund_rate"`
+
+	// Averaged over the fulfilled redemptions with a known resolve time; 0 when there aren't any.
+	AverageSecondsToFulfill float64 `json:"average_seconds_to_fulfill"`
+
+	timedFulfills int
+	fulfillTotal  time.Duration
+}
+
+// RewardStats are one reward's numbers.
+type RewardStats struct {
+	RewardID string `json:"reward_id"`
+	Title    string `json:"title"`
+	Counts
+
+	titleAt time.Time
+}
+
+// RedeemerStats are one viewer's numbers.
+type RedeemerStats struct {
+	UserID    string `json:"user_id"`
+	UserLogin string `json:"user_login"`
+	UserName  string `json:"user_name"`
+	Counts
+}
+
+// Stats are the numbers for redemptions in a time window.
+type Stats struct {
+	Since        time.Time       `json:"since,omitzero"`
+	Until        time.Time       `json:"until,omitzero"`
+	Total        Counts          `json:"total"`
+	Rewards      []RewardStats   `json:"rewards"`       // Most redeemed first
+	TopRedeemers []RedeemerStats `json:"top_redeemers"` // Most points spent first
+}
+
+func (c *Counts) add(redemption Redemption) {
+	c.Redemptions++
+	c.PointsSpent += redemption.Cost
+
+	switch redemption.Status {
+	case "FULFILLED":
+		c.Fulfilled++
+		if !redemption.ResolvedAt.IsZero() && redemption.ResolvedAt.After(redemption.RedeemedAt) {
+			c.timedFulfills++
+			c.fulfillTotal += redemption.ResolvedAt.Sub(redemption.RedeemedAt)
+		}
+	case "CANCELED":
+		c.Canceled++
+	default:
+		c.Unfulfilled++
+	}
+}
+
+func (c *Counts) finish() {
+	if c.Redemptions != 0 {
+		c.FulfillmentRate = float64(c.Fulfilled) / float64(c.Redemptions)
+		c.RefundRate = float64(c.Canceled) / float64(c.Redemptions)
+	}
+	if c.timedFulfills != 0 {
+		c.AverageSecondsToFulfill = (c.fulfillTotal / time.Duration(c.timedFulfills)).Seconds()
+	}
+}
+
+// ParseWindow reads a time window's ends, each like twitch.ParseSince takes. "all" or empty leaves that end open.
+// Returns since, until, and error.
+func ParseWindow(since string, until string) (time.Time, time.Time, error) {
+	var ends [2]time.Time
+	for i, end := range []string{since, until} {
+		if strings.EqualFold(strings.TrimSpace(end), "all") {
+			continue
+		}
+		parsed, err := twitch.ParseSince(end)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		ends[i] = parsed
+	}
+
+	if !ends[0].IsZero() && !ends[1].IsZero() && !ends[0].Before(ends[1]) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the window has to start before it ends")
+	}
+
+	return ends[0], ends[1], nil
+}
+
+// Compute works out the stats for redemptions made from since up to until. Zero times leave that end open.
+// top limits the redeemers listed; 0 lists all of them.
+func Compute(redemptions []Redemption, since time.Time, until time.Time, top int) Stats {
+	stats := Stats{Since: since, Until: until}
+	rewards := make(map[string]*RewardStats)
+	redeemers := make(map[string]*RedeemerStats)
+
+	for _, redemption := range redemptions {
+		if !since.IsZero() && redemption.RedeemedAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !redemption.RedeemedAt.Before(until) {
+			continue
+		}
+
+		stats.Total.add(redemption)
+
+		reward, ok := rewards[redemption.RewardID]
+		if !ok {
+			reward = &RewardStats{RewardID: redemption.RewardID}
+			rewards[redemption.RewardID] = reward
+		}
+		// Rewards can be renamed; the latest redemption has the current title.
+		if reward.Title == "" || redemption.RedeemedAt.After(reward.titleAt) {
+			reward.Title = redemption.RewardTitle
+			reward.titleAt = redemption.RedeemedAt
+		}
+		reward.add(redemption)
+
+		redeemer, ok := redeemers[redemption.UserID]
+		if !ok {
+			redeemer = &RedeemerStats{UserID: redemption.UserID}
+			redeemers[redemption.UserID] = redeemer
+		}
+		redeemer.UserLogin = redemption.UserLogin
+		redeemer.UserName = redemption.UserName
+		redeemer.add(redemption)
+	}
+
+	stats.Total.finish()
+
+	stats.Rewards = []RewardStats{}
+	for _, reward := range rewards {
+		reward.finish()
+		stats.Rewards = append(stats.Rewards, *reward)
+	}
+	sort.Slice(stats.Rewards, func(i, j int) bool {
+		if stats.Rewards[i].Redemptions != stats.Rewards[j].Redemptions {
+			return stats.Rewards[i].Redemptions > stats.Rewards[j].Redemptions
+		}
+		return stats.Rewards[i].Title < stats.Rewards[j].Title
+	})
+
+	stats.TopRedeemers = []RedeemerStats{}
+	for _, redeemer := range redeemers {
+		redeemer.finish()
+		stats.TopRedeemers = append(stats.TopRedeemers, *redeemer)
+	}
+	sort.Slice(stats.TopRedeemers, func(i, j int) bool {
+		if stats.TopRedeemers[i].PointsSpent != stats.TopRedeemers[j].PointsSpent {
+			return stats.TopRedeemers[i].PointsSpent > stats.TopRedeemers[j].PointsSpent
+		}
+		return stats.TopRedeemers[i].UserLogin < stats.TopRedeemers[j].UserLogin
+	})
+	if top > 0 && len(stats.TopRedeemers) > top {
+		stats.TopRedeemers = stats.TopRedeemers[:top]
+	}
+
+	return stats
+}
+
+// WriteStats writes stats as a table, csv, or json.
+// The csv has a row for the total, each reward, and each top redeemer, told apart by the first column.
+// Returns error.
+func WriteStats(w io.Writer, stats Stats, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"kind", "id", "name", "redemptions", "points_spent", "fulfilled", "canceled", "unfulfilled", "fulfillment_rate", "refund_rate", "average_seconds_to_fulfill"})
+		row := func(kind string, id string, name string, counts Counts) {
+			writer.Write([]string{
+				kind,
+				id,
+				name,
+				strconv.Itoa(counts.Redemptions),
+				strconv.Itoa(counts.PointsSpent),
+				strconv.Itoa(counts.Fulfilled),
+				strconv.Itoa(counts.Canceled),
+				strconv.Itoa(counts.Unfulfilled),
+				strconv.FormatFloat(counts.FulfillmentRate, 'f', 4, 64),
+				strconv.FormatFloat(counts.RefundRate, 'f', 4, 64),
+				strconv.FormatFloat(counts.AverageSecondsToFulfill, 'f', 1, 64),
+			})
+		}
+		row("total", "", "", stats.Total)
+		for _, reward := range stats.Rewards {
+			row("reward", reward.RewardID, reward.Title, reward.Counts)
+		}
+		for _, redeemer := range stats.TopRedeemers {
+			row("redeemer", redeemer.UserID, redeemer.UserLogin, redeemer.Counts)
+		}
+		writer.Flush()
+		return writer.Error()
+	case "table":
+		fmt.Fprintf(w, "Redemptions %s: %d, %d points spent\n", window(stats), stats.Total.Redemptions, stats.Total.PointsSpent)
+		fmt.Fprintf(w, "Fulfilled %s, refunded %s, average time to fulfill %s\n\n", percent(stats.Total.FulfillmentRate), percent(stats.Total.RefundRate), fulfillTime(stats.Total))
+
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(table, "Reward\tRedemptions\tPoints\tFulfilled\tRefunded\tAvg. to fulfill\t\n")
+		for _, reward := range stats.Rewards {
+			fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%s\t%s\t\n", reward.Title, reward.Redemptions, reward.PointsSpent, percent(reward.FulfillmentRate), percent(reward.RefundRate), fulfillTime(reward.Counts))
+		}
+		table.Flush()
+
+		if len(stats.TopRedeemers) != 0 {
+			fmt.Fprintf(w, "\nTop redeemers:\n")
+			table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(table, "#\tUser\tRedemptions\tPoints\t\n")
+			for i, redeemer := range stats.TopRedeemers {
+				fmt.Fprintf(table, "%d\t%s\t%d\t%d\t\n", i+1, redeemer.UserName, redeemer.Redemptions, redeemer.PointsSpent)
+			}
+			table.Flush()
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown stats format %s; use %s", format, strings.Join(Formats, ", "))
+}
+
+func window(stats Stats) string {
+	switch {
+	case stats.Since.IsZero() && stats.Until.IsZero():
+		return "recorded"
+	case stats.Until.IsZero():
+		return "since " + stats.Since.Local().Format("2006-01-02 15:04")
+	case stats.Since.IsZero():
+		return "before " + stats.Until.Local().Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("from %s to %s", stats.Since.Local().Format("2006-01-02 15:04"), stats.Until.Local().Format("2006-01-02 15:04"))
+}
+
+func percent(rate float64) string {
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+func fulfillTime(counts Counts) string {
+	if counts.timedFulfills == 0 {
+		return "-"
+	}
+	return time.Duration(counts.AverageSecondsToFulfill * float64(time.Second)).Round(time.Second).String()
+}
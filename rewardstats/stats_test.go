@@ -0,0 +1,186 @@
+package rewardstats
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		since     string
+		until     string
+		openSince bool
+		openUntil bool
+		expectErr bool
+	}{
+		{name: "open", since: "", until: "", openSince: true, openUntil: true},
+		{name: "all", since: "all", until: "ALL", openSince: true, openUntil: true},
+		{name: "since only", since: "7d", until: "", openUntil: true},
+		{name: "until only", since: "", until: "1d", openSince: true},
+		{name: "both", since: "2w", until: "1w"},
+		{name: "dates", since: "2024-01-01", until: "2024-02-01"},
+		{name: "starts after it ends", since: "1d", until: "7d", expectErr: true},
+		{name: "same time", since: "2024-01-01", until: "2024-01-01", expectErr: true},
+		{name: "bad since", since: "soon", expectErr: true},
+		{name: "bad until", until: "later", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			since, until, err := ParseWindow(test.since, test.until)
+			if test.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v to %v", since, until)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if since.IsZero() != test.openSince {
+				t.Errorf("since %v, expected open: %t", since, test.openSince)
+			}
+			if until.IsZero() != test.openUntil {
+				t.Errorf("until %v, expected open: %t", until, test.openUntil)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	redemptions := []Redemption{
+		{ID: "1", RewardID: "hydrate", RewardTitle: "Hydrate", Cost: 100, UserID: "u1", UserLogin: "alice", Status: "FULFILLED", RedeemedAt: at(0), ResolvedAt: at(2)},
+		{ID: "2", RewardID: "hydrate", RewardTitle: "Hydrate", Cost: 100, UserID: "u2", UserLogin: "bob", Status: "FULFILLED", RedeemedAt: at(10), ResolvedAt: at(14)},
+		{ID: "3", RewardID: "hydrate", RewardTitle: "Drink water", Cost: 100, UserID: "u1", UserLogin: "alice", Status: "CANCELED", RedeemedAt: at(20), ResolvedAt: at(21)},
+		{ID: "4", RewardID: "song", RewardTitle: "Song request", Cost: 500, UserID: "u2", UserLogin: "bob", Status: "UNFULFILLED", RedeemedAt: at(30)},
+		{ID: "5", RewardID: "song", RewardTitle: "Song request", Cost: 500, UserID: "u3", UserLogin: "carol", Status: "FULFILLED", RedeemedAt: at(40)},          // Backfilled, no resolve time
+		{ID: "6", RewardID: "hug", RewardTitle: "Hug", Cost: 50, UserID: "u3", UserLogin: "carol", Status: "FULFILLED", RedeemedAt: at(50), ResolvedAt: at(49)}, // Resolved before redeemed, so not timed
+	}
+
+	type counts struct {
+		redemptions, points, fulfilled, canceled, unfulfilled int
+		fulfillmentRate, refundRate, averageSeconds           float64
+	}
+	tests := []struct {
+		name      string
+		since     time.Time
+		until     time.Time
+		top       int
+		total     counts
+		rewards   []string // Titles, in order
+		redeemers []string // Logins, in order
+	}{
+		{
+			name:      "everything",
+			total:     counts{6, 1350, 4, 1, 1, 4.0 / 6, 1.0 / 6, 180},
+			rewards:   []string{"Drink water", "Song request", "Hug"},
+			redeemers: []string{"bob", "carol", "alice"},
+		},
+		{
+			name:      "since is inclusive",
+			since:     at(30),
+			total:     counts{3, 1050, 2, 0, 1, 2.0 / 3, 0, 0},
+			rewards:   []string{"Song request", "Hug"},
+			redeemers: []string{"carol", "bob"},
+		},
+		{
+			name:      "until is exclusive",
+			until:     at(20),
+			total:     counts{2, 200, 2, 0, 0, 1, 0, 180},
+			rewards:   []string{"Hydrate"},
+			redeemers: []string{"alice", "bob"},
+		},
+		{
+			name:      "top redeemers",
+			top:       1,
+			total:     counts{6, 1350, 4, 1, 1, 4.0 / 6, 1.0 / 6, 180},
+			rewards:   []string{"Drink water", "Song request", "Hug"},
+			redeemers: []string{"bob"},
+		},
+		{
+			name:      "nothing in the window",
+			since:     at(100),
+			total:     counts{},
+			rewards:   []string{},
+			redeemers: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stats := Compute(redemptions, test.since, test.until, test.top)
+
+			total := counts{
+				stats.Total.Redemptions, stats.Total.PointsSpent, stats.Total.Fulfilled, stats.Total.Canceled, stats.Total.Unfulfilled,
+				stats.Total.FulfillmentRate, stats.Total.RefundRate, stats.Total.AverageSecondsToFulfill,
+			}
+			if total.redemptions != test.total.redemptions || total.points != test.total.points ||
+				total.fulfilled != test.total.fulfilled || total.canceled != test.total.canceled || total.unfulfilled != test.total.unfulfilled ||
+				!near(total.fulfillmentRate, test.total.fulfillmentRate) || !near(total.refundRate, test.total.refundRate) ||
+				!near(total.averageSeconds, test.total.averageSeconds) {
+				t.Errorf("total %+v, expected %+v", total, test.total)
+			}
+
+			rewards := []string{}
+			for _, reward := range stats.Rewards {
+				rewards = append(rewards, reward.Title)
+			}
+			if !slices.Equal(rewards, test.rewards) {
+				t.Errorf("rewards %q, expected %q", rewards, test.rewards)
+			}
+
+			redeemers := []string{}
+			for _, redeemer := range stats.TopRedeemers {
+				redeemers = append(redeemers, redeemer.UserLogin)
+			}
+			if !slices.Equal(redeemers, test.redeemers) {
+				t.Errorf("top redeemers %q, expected %q", redeemers, test.redeemers)
+			}
+		})
+	}
+}
+
+func TestComputePerReward(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	redemptions := []Redemption{
+		{ID: "1", RewardID: "r", RewardTitle: "Old title", Cost: 10, UserID: "u1", Status: "FULFILLED", RedeemedAt: start, ResolvedAt: start.Add(30 * time.Second)},
+		{ID: "2", RewardID: "r", RewardTitle: "New title", Cost: 20, UserID: "u1", Status: "FULFILLED", RedeemedAt: start.Add(time.Hour), ResolvedAt: start.Add(time.Hour + 90*time.Second)},
+		{ID: "3", RewardID: "r", RewardTitle: "Old title", Cost: 10, UserID: "u2", Status: "CANCELED", RedeemedAt: start.Add(-time.Hour)},
+		{ID: "4", RewardID: "r", RewardTitle: "Old title", Cost: 10, UserID: "u2", Status: "UNFULFILLED", RedeemedAt: start.Add(time.Minute)},
+	}
+
+	stats := Compute(redemptions, time.Time{}, time.Time{}, 0)
+	if len(stats.Rewards) != 1 {
+		t.Fatalf("expected 1 reward, got %d", len(stats.Rewards))
+	}
+
+	reward := stats.Rewards[0]
+	if reward.Title != "New title" {
+		t.Errorf("title %q, expected the latest one", reward.Title)
+	}
+	if reward.Redemptions != 4 || reward.PointsSpent != 50 || reward.Fulfilled != 2 || reward.Canceled != 1 || reward.Unfulfilled != 1 {
+		t.Errorf("counts %+v", reward.Counts)
+	}
+	if !near(reward.FulfillmentRate, 0.5) || !near(reward.RefundRate, 0.25) {
+		t.Errorf("fulfillment rate %v and refund rate %v, expected 0.5 and 0.25", reward.FulfillmentRate, reward.RefundRate)
+	}
+	if !near(reward.AverageSecondsToFulfill, 60) {
+		t.Errorf("average seconds to fulfill %v, expected 60", reward.AverageSecondsToFulfill)
+	}
+
+	if len(stats.TopRedeemers) != 2 || stats.TopRedeemers[0].UserID != "u1" || stats.TopRedeemers[0].PointsSpent != 30 {
+		t.Errorf("top redeemers %+v, expected u1 first with 30 points", stats.TopRedeemers)
+	}
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
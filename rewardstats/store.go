@@ -0,0 +1,137 @@
+package rewardstats
+
+import (
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Recorded redemptions, by channel ID and then redemption ID, so recording the same one twice only updates it.
+const recordFile = "reward-stats.json"
+
+// Redemption is one recorded redemption.
+// ResolvedAt is when it was seen being fulfilled or canceled. It's only known for redemptions that were recorded
+// live; backfilled ones that were already resolved leave it zero.
+type Redemption struct {
+	ID          string    `json:"id"`
+	RewardID    string    `json:"reward_id"`
+	RewardTitle string    `json:"reward_title"`
+	Cost        int       `json:"cost"`
+	UserID      string    `json:"user_id"`
+	UserLogin   string    `json:"user_login"`
+	UserName    string    `json:"user_name"`
+	Input       string    `json:"input,omitempty"`
+	Status      string    `json:"status"` // UNFULFILLED, FULFILLED, or CANCELED
+	RedeemedAt  time.Time `json:"redeemed_at"`
+	ResolvedAt  time.Time `json:"resolved_at,omitzero"`
+}
+
+// FromEvent makes a Redemption from an EventSub redemption event seen at the given time.
+func FromEvent(event twitch.RedemptionEvent, seen time.Time) Redemption {
+	redemption := Redemption{
+		ID:          event.ID,
+		RewardID:    event.Reward.ID,
+		RewardTitle: event.Reward.Title,
+		Cost:        event.Reward.Cost,
+		UserID:      event.UserID,
+		UserLogin:   event.UserLogin,
+		UserName:    event.UserName,
+		Input:       event.UserInput,
+		Status:      strings.ToUpper(event.Status), // EventSub sends it lower case, unlike Helix
+		RedeemedAt:  event.RedeemedAt,
+	}
+	if redemption.Status != "UNFULFILLED" {
+		redemption.ResolvedAt = seen
+	}
+	return redemption
+}
+
+// FromHelix makes a Redemption from one returned by Get Custom Reward Redemption.
+func FromHelix(redemption helix.ChannelCustomRewardsRedemption) Redemption {
+	return Redemption{
+		ID:          redemption.ID,
+		RewardID:    redemption.Reward.ID,
+		RewardTitle: redemption.Reward.Title,
+		Cost:        redemption.Reward.Cost,
+		UserID:      redemption.UserID,
+		UserLogin:   redemption.UserLogin,
+		UserName:    redemption.UserName,
+		Input:       redemption.UserInput,
+		Status:      redemption.Status,
+		RedeemedAt:  redemption.RedeemedAt.Time,
+	}
+}
+
+// Record adds redemptions to a channel's store, or updates ones already there. A known resolve time is never
+// overwritten, so backfilling after recording live keeps the live times.
+// Returns error.
+func Record(channelID string, redemptions ...Redemption) error {
+	records := make(map[string]map[string]Redemption)
+	return store.Update(recordFile, &records, func() error {
+		if records[channelID] == nil {
+			records[channelID] = make(map[string]Redemption)
+		}
+
+		for _, redemption := range redemptions {
+			if existing, ok := records[channelID][redemption.ID]; ok {
+				if redemption.ResolvedAt.IsZero() {
+					redemption.ResolvedAt = existing.ResolvedAt
+				}
+				// An update event has no reason to carry the input; keep the one from the add event.
+				if redemption.Input == "" {
+					redemption.Input = existing.Input
+				}
+			}
+			records[channelID][redemption.ID] = redemption
+		}
+
+		return nil
+	})
+}
+
+// Load returns every redemption recorded on a channel.
+func Load(channelID string) ([]Redemption, error) {
+	records := make(map[string]map[string]Redemption)
+	if err := store.Load(recordFile, &records); err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]Redemption, 0, len(records[channelID]))
+	for _, redemption := range records[channelID] {
+		redemptions = append(redemptions, redemption)
+	}
+
+	return redemptions, nil
+}
+
+// Backfill records every redemption Twitch still has for the channel's rewards, of every status.
+// Twitch only returns redemptions of rewards msc created.
+// Returns how many were recorded and error.
+func Backfill(c helix.Client, channelID string) (int, error) {
+	rewards, err := twitch.GetManageableRewards(c, channelID)
+	if err != nil {
+		return 0, err
+	}
+
+	var redemptions []Redemption
+	for _, reward := range rewards {
+		for _, status := range []string{"UNFULFILLED", "FULFILLED", "CANCELED"} {
+			page, err := twitch.ListRedemptions(c, channelID, reward.ID, status, "OLDEST", 0)
+			if err != nil {
+				return 0, err
+			}
+			for _, redemption := range page {
+				redemptions = append(redemptions, FromHelix(redemption))
+			}
+		}
+	}
+
+	if len(redemptions) == 0 {
+		return 0, nil
+	}
+
+	return len(redemptions), Record(channelID, redemptions...)
+}
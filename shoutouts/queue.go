@@ -0,0 +1,341 @@
+package shoutouts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/monktype/msc/store"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// Twitch's shoutout cooldowns: one shoutout per channel every 2 minutes, and the same target once an hour.
+const (
+	GlobalCooldown = 2 * time.Minute
+	TargetCooldown = 60 * time.Minute
+)
+
+// A shoutout that keeps failing (not a cooldown we know about) is dropped after this many tries.
+const maxAttempts = 3
+
+const queueFile = "shoutouts.json"
+
+// Entry is one queued shoutout. ETA is filled in by the functions that return entries.
+type Entry struct {
+	ChannelID  string    `json:"channel_id"`
+	UserID     string    `json:"user_id"` // Moderator sending it
+	TargetID   string    `json:"target_id"`
+	TargetName string    `json:"target_name"`
+	QueuedAt   time.Time `json:"queued_at"`
+	Attempts   int       `json:"attempts"`
+	ETA        time.Time `json:"eta"`
+}
+
+// Target returns the name to show for the target, or its ID if the name isn't known.
+func (e Entry) Target() string {
+	if e.TargetName != "" {
+		return e.TargetName
+	}
+	return e.TargetID
+}
+
+// queue is the state file: the waiting shoutouts and when shoutouts were last sent, which the cooldowns count from.
+type queue struct {
+	Entries     []Entry              `json:"entries"`
+	ChannelLast map[string]time.Time `json:"channel_last"` // Channel ID -> last shoutout
+	TargetLast  map[string]time.Time `json:"target_last"`  // Channel ID + "/" + target ID -> last shoutout
+}
+
+func targetKey(channelID string, targetID string) string {
+	return channelID + "/" + targetID
+}
+
+func loadQueue() (queue, error) {
+	q := queue{}
+	err := store.Load(queueFile, &q)
+	return q, err
+}
+
+func updateQueue(change func(q *queue) error) error {
+	q := queue{}
+	return store.Update(queueFile, &q, func() error {
+		if q.ChannelLast == nil {
+			q.ChannelLast = make(map[string]time.Time)
+		}
+		if q.TargetLast == nil {
+			q.TargetLast = make(map[string]time.Time)
+		}
+		return change(&q)
+	})
+}
+
+// schedule works out when each entry will go out, in queue order except that an entry whose target is still
+// cooling down lets the entries behind it go first.
+// Returns the entries in the order they'll be sent, with ETA set.
+func (q queue) schedule(now time.Time) []Entry {
+	channelReady := make(map[string]time.Time)
+	targetReady := make(map[string]time.Time)
+	for channelID, last := range q.ChannelLast {
+		channelReady[channelID] = last.Add(GlobalCooldown)
+	}
+	for key, last := range q.TargetLast {
+		targetReady[key] = last.Add(TargetCooldown)
+	}
+
+	ready := func(e Entry) time.Time {
+		eta := now
+		if t := channelReady[e.ChannelID]; t.After(eta) {
+			eta = t
+		}
+		if t := targetReady[targetKey(e.ChannelID, e.TargetID)]; t.After(eta) {
+			eta = t
+		}
+		return eta
+	}
+
+	waiting := make([]Entry, len(q.Entries))
+	copy(waiting, q.Entries)
+
+	var scheduled []Entry
+	for len(waiting) != 0 {
+		next := 0
+		for i := range waiting {
+			if ready(waiting[i]).Before(ready(waiting[next])) {
+				next = i
+			}
+		}
+
+		e := waiting[next]
+		e.ETA = ready(e)
+		scheduled = append(scheduled, e)
+
+		channelReady[e.ChannelID] = e.ETA.Add(GlobalCooldown)
+		targetReady[targetKey(e.ChannelID, e.TargetID)] = e.ETA.Add(TargetCooldown)
+		waiting = append(waiting[:next], waiting[next+1:]...)
+	}
+
+	return scheduled
+}
+
+// Result is what happened to a shoutout given to Enqueue.
+type Result struct {
+	Entry    Entry  `json:"entry"`
+	Position int    `json:"position"` // 1-based place in the channel's send order; 0 if skipped
+	Skipped  string `json:"skipped"`  // Why it wasn't queued, if it wasn't
+}
+
+// Enqueue adds a shoutout to the queue.
+// It's skipped if the target is already queued on the channel or was shouted out there less than TargetCooldown ago.
+// Returns Result and error.
+func Enqueue(userID string, channelID string, targetID string, targetName string) (Result, error) {
+	result := Result{Entry: Entry{
+		ChannelID:  channelID,
+		UserID:     userID,
+		TargetID:   targetID,
+		TargetName: targetName,
+		QueuedAt:   time.Now(),
+	}}
+
+	err := updateQueue(func(q *queue) error {
+		for _, e := range q.Entries {
+			if e.ChannelID == channelID && e.TargetID == targetID {
+				result.Skipped = "already queued"
+				return nil
+			}
+		}
+
+		if last, ok := q.TargetLast[targetKey(channelID, targetID)]; ok && time.Since(last) < TargetCooldown {
+			result.Skipped = fmt.Sprintf("shouted out at %s, on cooldown until %s", last.Local().Format("15:04"), last.Add(TargetCooldown).Local().Format("15:04"))
+			return nil
+		}
+
+		q.Entries = append(q.Entries, result.Entry)
+
+		position := 0
+		for _, e := range q.schedule(time.Now()) {
+			if e.ChannelID != channelID {
+				continue
+			}
+			position++
+			if e.TargetID == targetID {
+				result.Entry = e
+				result.Position = position
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// Pending returns the queued shoutouts for a channel (every channel if channelID is empty) in send order, with ETAs.
+// Returns []Entry and error.
+func Pending(channelID string) ([]Entry, error) {
+	q, err := loadQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, e := range q.schedule(time.Now()) {
+		if channelID == "" || e.ChannelID == channelID {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+// Remove takes a target out of a channel's queue.
+// Returns true if it was queued, and error.
+func Remove(channelID string, targetID string) (bool, error) {
+	removed := false
+	err := updateQueue(func(q *queue) error {
+		for i, e := range q.Entries {
+			if e.ChannelID == channelID && e.TargetID == targetID {
+				q.Entries = append(q.Entries[:i], q.Entries[i+1:]...)
+				removed = true
+				return nil
+			}
+		}
+		return nil
+	})
+
+	return removed, err
+}
+
+// RecordSent notes a shoutout sent outside the queue (msc shoutout without --queue), so queued shoutouts wait out the
+// channel and target cooldowns it started. A queued shoutout for the same target is dropped, since it just went out.
+// Returns error.
+func RecordSent(channelID string, targetID string, at time.Time) error {
+	return updateQueue(func(q *queue) error {
+		q.ChannelLast[channelID] = at
+		q.TargetLast[targetKey(channelID, targetID)] = at
+
+		for i, e := range q.Entries {
+			if e.ChannelID == channelID && e.TargetID == targetID {
+				q.Entries = append(q.Entries[:i], q.Entries[i+1:]...)
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// SendDue sends every queued shoutout whose cooldowns have passed, at most one per channel per call.
+// onSent is called for each shoutout sent and onFailed for each one that failed; either can be nil.
+// A failed shoutout is retried after the global cooldown (someone may have sent a shoutout outside msc)
+// and dropped after a few failures.
+// Returns error for failures reading or writing the queue; Twitch failures go to onFailed.
+func SendDue(c helix.Client, onSent func(Entry), onFailed func(Entry, error, bool)) error {
+	var due []Entry
+	err := updateQueue(func(q *queue) error {
+		now := time.Now()
+		seen := make(map[string]bool)
+		for _, e := range q.schedule(now) {
+			if e.ETA.After(now) || seen[e.ChannelID] {
+				continue
+			}
+			seen[e.ChannelID] = true
+			due = append(due, e)
+		}
+
+		// Take them out of the queue before sending, so another worker doesn't pick them up too.
+		for _, d := range due {
+			for i, e := range q.Entries {
+				if e.ChannelID == d.ChannelID && e.TargetID == d.TargetID {
+					q.Entries = append(q.Entries[:i], q.Entries[i+1:]...)
+					break
+				}
+			}
+			q.ChannelLast[d.ChannelID] = now
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range due {
+		sendErr := twitch.SendShoutout(c, e.UserID, e.ChannelID, e.TargetID)
+
+		err := updateQueue(func(q *queue) error {
+			if sendErr == nil {
+				q.TargetLast[targetKey(e.ChannelID, e.TargetID)] = time.Now()
+				return nil
+			}
+
+			e.Attempts++
+			if e.Attempts < maxAttempts {
+				q.Entries = append([]Entry{e}, q.Entries...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if sendErr == nil {
+			if onSent != nil {
+				onSent(e)
+			}
+		} else if onFailed != nil {
+			onFailed(e, sendErr, e.Attempts >= maxAttempts)
+		}
+	}
+
+	return nil
+}
+
+// NextDue returns when the next queued shoutout (on any channel) can go out, or the zero time if the queue is empty.
+// Returns time.Time and error.
+func NextDue() (time.Time, error) {
+	entries, err := Pending("")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(entries) == 0 {
+		return time.Time{}, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ETA.Before(entries[j].ETA) })
+	return entries[0].ETA, nil
+}
+
+// How often Work checks the queue.
+const workInterval = 5 * time.Second
+
+// Work sends queued shoutouts as they come due, until stop is closed.
+// It gets a fresh client for each batch, so it can run for as long as the process does.
+func Work(stop <-chan struct{}, onSent func(Entry), onFailed func(Entry, error, bool)) {
+	ticker := time.NewTicker(workInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			next, err := NextDue()
+			if err != nil {
+				fmt.Printf("Failed to read the shoutout queue: %s\n", err)
+				continue
+			}
+			if next.IsZero() || next.After(time.Now()) {
+				continue
+			}
+
+			c, err := twitch.GetClient()
+			if err != nil {
+				fmt.Printf("Failed to get client for queued shoutouts, trying again: %s\n", err)
+				continue
+			}
+
+			if err := SendDue(c, onSent, onFailed); err != nil {
+				fmt.Printf("Failed to update the shoutout queue: %s\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// This is the directory under the user's config directory that msc keeps local state in.
+var dirName = "msc"
+
+// Appends from one process don't interleave. Update and Lock go further with a lock file, since msc commands and the
+// API server can run side by side and change the same state.
+var appendLock sync.Mutex
+
+// How long Lock waits for another holder, and how old a lock file has to be to count as left behind by a process that
+// crashed. Holders keep the lock for a few network calls at most.
+const (
+	lockWait  = time.Minute
+	lockStale = 2 * time.Minute
+	lockRetry = 50 * time.Millisecond
+)
+
+// Dir returns the directory msc keeps local state in (queues, snapshots, timer state), creating it if needed.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, dirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// Path returns the full path of a state file by name.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// Load reads the named state file as JSON into v.
+// A file that doesn't exist yet leaves v as it is and isn't an error.
+func Load(name string, v interface{}) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// Save writes v as JSON to the named state file.
+// It writes a temporary file and renames it over the old one so a reader never sees half a file.
+func Save(name string, v interface{}) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// A temporary file of its own, so two processes saving at once can't write into the same one.
+	tmp, err := os.CreateTemp(filepath.Dir(path), name+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
+// Lock takes the named state file's lock, which every msc process respects, for a change that needs more than Update
+// gives (like reading from Twitch between the load and the save). It waits for another holder for up to a minute.
+// Returns a function that releases the lock, and error.
+func Lock(name string) (func(), error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	path += ".lock"
+
+	deadline := time.Now().Add(lockWait)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another msc process; if none is running, delete %s", name, path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// Update loads the named state file into v, calls change, and saves v if change returns nil, holding the file's Lock
+// throughout.
+func Update(name string, v interface{}, change func() error) error {
+	unlock, err := Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := Load(name, v); err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	return Save(name, v)
+}
+
+// Remove deletes the named state file; a file that doesn't exist isn't an error.
+func Remove(name string) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Append adds v as one line of JSON to the end of the named file, for logs that only grow.
+func Append(name string, v interface{}) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	appendLock.Lock()
+	defer appendLock.Unlock()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+package timers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/monktype/msc/twitch"
+)
+
+// Timer is one recurring message from the config file.
+// Messages are sent in rotation, one per firing; they can use the announcement template variables.
+type Timer struct {
+	Name        string        `yaml:"name" json:"name"`
+	Type        string        `yaml:"type" json:"type"` // "announcement" (default) or "chat"
+	Interval    time.Duration `yaml:"interval" json:"interval"`
+	Jitter      time.Duration `yaml:"jitter" json:"jitter"`             // Each wait is moved up to this much either way
+	MinMessages int           `yaml:"min_messages" json:"min_messages"` // Chat messages needed since the last firing
+	Color       string        `yaml:"color" json:"color"`               // Announcement border color; defaults to primary
+	Enabled     *bool         `yaml:"enabled" json:"enabled"`           // Defaults to true
+	Messages    []string      `yaml:"messages" json:"messages"`
+}
+
+// Config is the timers config file.
+type Config struct {
+	Channel string  `yaml:"channel"` // Optional; the -c flag wins
+	Timers  []Timer `yaml:"timers"`
+}
+
+// The shortest interval allowed, to keep a typo from flooding chat.
+const MinInterval = 30 * time.Second
+
+// IsEnabled reports whether the timer is enabled; timers without an enabled field are.
+func (t Timer) IsEnabled() bool {
+	return t.Enabled == nil || *t.Enabled
+}
+
+// AnnouncementColor returns the timer's announcement color.
+func (t Timer) AnnouncementColor() (twitch.AnnouncementColor, error) {
+	if t.Color == "" {
+		return twitch.AnnouncementColorPrimary, nil
+	}
+
+	for color, name := range twitch.AnnouncementColorMap {
+		if strings.EqualFold(t.Color, name) {
+			return color, nil
+		}
+	}
+
+	return twitch.AnnouncementColorPrimary, fmt.Errorf("timer %s: color %s is invalid; use primary, blue, green, orange, or purple", t.Name, t.Color)
+}
+
+// LoadConfig reads and checks a timers config file.
+// Returns Config and error.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	if len(config.Timers) == 0 {
+		return config, fmt.Errorf("%s has no timers", path)
+	}
+
+	names := make(map[string]bool)
+	for i := range config.Timers {
+		t := &config.Timers[i]
+
+		if t.Name == "" {
+			return config, fmt.Errorf("timer %d has no name", i+1)
+		}
+		if names[t.Name] {
+			return config, fmt.Errorf("timer name %s is used more than once", t.Name)
+		}
+		names[t.Name] = true
+
+		if t.Type == "" {
+			t.Type = "announcement"
+		}
+		if t.Type != "announcement" && t.Type != "chat" {
+			return config, fmt.Errorf("timer %s: type can only be announcement or chat", t.Name)
+		}
+
+		if t.Interval < MinInterval {
+			return config, fmt.Errorf("timer %s: interval must be at least %s", t.Name, MinInterval)
+		}
+		if t.Jitter < 0 || t.Jitter >= t.Interval {
+			return config, fmt.Errorf("timer %s: jitter must be at least 0 and less than the interval", t.Name)
+		}
+		if t.MinMessages < 0 {
+			return config, fmt.Errorf("timer %s: min_messages can't be negative", t.Name)
+		}
+		if len(t.Messages) == 0 {
+			return config, fmt.Errorf("timer %s has no messages", t.Name)
+		}
+
+		if _, err := t.AnnouncementColor(); err != nil {
+			return config, err
+		}
+	}
+
+	return config, nil
+}
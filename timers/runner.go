@@ -0,0 +1,293 @@
+package timers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/monktype/msc/eventsub"
+	"github.com/monktype/msc/twitch"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// How often the runner checks for due timers, and how long a live check is trusted.
+const (
+	tickInterval      = 5 * time.Second
+	liveCheckInterval = time.Minute
+)
+
+type runningTimer struct {
+	Timer
+	nextFire          time.Time
+	lastFired         time.Time
+	messagesSinceFire int
+	nextMessage       int
+}
+
+// Runner fires the timers from a Config on one channel.
+type Runner struct {
+	channelID string
+	userID    string
+	timers    []*runningTimer
+
+	lock       sync.Mutex
+	session    *eventsub.Session
+	live       bool
+	liveLooked time.Time
+}
+
+type chatMessageEvent struct {
+	ChatterUserID string `json:"chatter_user_id"`
+}
+
+// NewRunner sets up the timers in config to run on the channel as the user.
+// If any timer needs a minimum number of chat messages, it subscribes to channel.chat.message to count them
+// (this needs the user:read:chat scope).
+// Message rotation continues from where the last run on the same channel left off.
+// Returns *Runner and error.
+func NewRunner(c helix.Client, config Config, userID string, channelID string) (*Runner, error) {
+	r := &Runner{
+		channelID: channelID,
+		userID:    userID,
+	}
+
+	previous := make(map[string]Status)
+	if status, err := GetStatus(); err == nil && status.ChannelID == channelID {
+		for _, timer := range status.Timers {
+			previous[timer.Name] = timer
+		}
+	}
+
+	needsChat := false
+	now := time.Now()
+	for _, timer := range config.Timers {
+		t := &runningTimer{Timer: timer}
+		t.nextFire = now.Add(t.wait())
+		if last, ok := previous[timer.Name]; ok {
+			t.nextMessage = last.NextMessage % len(timer.Messages)
+		}
+		r.timers = append(r.timers, t)
+
+		if timer.IsEnabled() && timer.MinMessages > 0 {
+			needsChat = true
+		}
+	}
+
+	if needsChat {
+		session, err := eventsub.Connect()
+		if err != nil {
+			fmt.Printf("Failed to start EventSub session: %s\n", err)
+			return nil, err
+		}
+
+		err = twitch.SubscribeEventSub(c, session.ID, "channel.chat.message", "1", map[string]string{
+			"broadcaster_user_id": channelID,
+			"user_id":             userID,
+		})
+		if err != nil {
+			session.Close()
+			return nil, err
+		}
+
+		r.session = session
+	}
+
+	return r, nil
+}
+
+// wait is the time until the timer's next firing: the interval, moved by a random amount up to the jitter.
+func (t *runningTimer) wait() time.Duration {
+	if t.Jitter <= 0 {
+		return t.Interval
+	}
+	return t.Interval - t.Jitter + rand.N(2*t.Jitter+1)
+}
+
+func (r *Runner) handleNotification(n eventsub.Notification) {
+	if n.Type != "channel.chat.message" {
+		return
+	}
+
+	var event chatMessageEvent
+	if err := json.Unmarshal(n.Event, &event); err != nil {
+		fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+		return
+	}
+	if event.ChatterUserID == r.userID {
+		return // Our own messages (including the timers') don't count.
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, t := range r.timers {
+		t.messagesSinceFire++
+	}
+}
+
+// Run fires timers until stop is closed, or until counting chat messages fails.
+// Returns nil when stopped, otherwise the error that ended it.
+func (r *Runner) Run(stop <-chan struct{}) error {
+	chatErr := make(chan error, 1)
+	if r.session != nil {
+		go func() {
+			chatErr <- r.session.Listen(r.handleNotification)
+		}()
+		defer r.session.Close()
+	}
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	r.tick()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-chatErr:
+			if err != nil {
+				fmt.Printf("Stopped counting chat messages: %s\n", err)
+			}
+			return err
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// tick fires every timer that's due, enabled, unpaused, and has seen enough chat, if the channel is live.
+func (r *Runner) tick() {
+	paused, err := GetPauseState()
+	if err != nil {
+		fmt.Printf("Failed to read paused timers, trying again next time: %s\n", err)
+		return
+	}
+
+	now := time.Now()
+	var due []*runningTimer
+
+	r.lock.Lock()
+	for _, t := range r.timers {
+		if !t.IsEnabled() || paused.IsPaused(t.Name) || now.Before(t.nextFire) || t.messagesSinceFire < t.MinMessages {
+			continue
+		}
+		due = append(due, t)
+	}
+	r.lock.Unlock()
+
+	if len(due) != 0 {
+		// Get a fresh client in case the runner has been going long enough for the token to need a refresh.
+		c, err := twitch.GetClient()
+		if err != nil {
+			fmt.Printf("Failed to get client, trying again next time: %s\n", err)
+		} else if r.isLive(c, now) {
+			for _, t := range due {
+				r.fire(c, t)
+			}
+		}
+	}
+
+	r.saveStatus(paused)
+}
+
+// isLive checks whether the channel is live, at most once per liveCheckInterval.
+func (r *Runner) isLive(c helix.Client, now time.Time) bool {
+	if now.Sub(r.liveLooked) < liveCheckInterval {
+		return r.live
+	}
+
+	_, live, err := twitch.GetStream(c, r.channelID)
+	if err != nil {
+		return r.live // Keep the last answer; it's checked again next time.
+	}
+
+	if live != r.live {
+		if live {
+			fmt.Printf("%s Channel is live; timers are running.\n", time.Now().Format("15:04:05"))
+		} else {
+			fmt.Printf("%s Channel is offline; timers are waiting.\n", time.Now().Format("15:04:05"))
+		}
+	}
+	r.live = live
+	r.liveLooked = now
+
+	return live
+}
+
+func (r *Runner) fire(c helix.Client, t *runningTimer) {
+	r.lock.Lock()
+	text := t.Messages[t.nextMessage]
+	r.lock.Unlock()
+
+	var err error
+	if t.Type == "chat" {
+		err = sendChat(c, r.userID, r.channelID, text)
+	} else {
+		var color twitch.AnnouncementColor
+		color, err = t.AnnouncementColor()
+		if err == nil {
+			var announcements []string
+			announcements, err = twitch.RenderAnnouncements(c, r.channelID, text, nil)
+			if err == nil {
+				err = twitch.SendAnnouncements(c, r.userID, r.channelID, color, announcements, twitch.DefaultAnnouncementDelay)
+			}
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err != nil {
+		// Wait a full interval rather than retrying every tick against whatever went wrong.
+		fmt.Printf("%s Timer %s failed: %s\n", time.Now().Format("15:04:05"), t.Name, err)
+		t.nextFire = time.Now().Add(t.wait())
+		return
+	}
+
+	fmt.Printf("%s Timer %s fired: %s\n", time.Now().Format("15:04:05"), t.Name, text)
+	t.lastFired = time.Now()
+	t.nextFire = t.lastFired.Add(t.wait())
+	t.messagesSinceFire = 0
+	t.nextMessage = (t.nextMessage + 1) % len(t.Messages)
+}
+
+func sendChat(c helix.Client, userID string, channelID string, text string) error {
+	rendered, err := twitch.RenderTemplate(c, channelID, text, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range twitch.SplitMessage(rendered, twitch.AnnouncementMaxLength) {
+		if err := twitch.SendChatMessage(c, userID, channelID, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) saveStatus(paused PauseState) {
+	r.lock.Lock()
+	status := RunnerStatus{
+		ChannelID: r.channelID,
+		Live:      r.live,
+		UpdatedAt: time.Now(),
+	}
+	for _, t := range r.timers {
+		status.Timers = append(status.Timers, Status{
+			Name:              t.Name,
+			Enabled:           t.IsEnabled(),
+			Paused:            paused.IsPaused(t.Name),
+			NextFire:          t.nextFire,
+			LastFired:         t.lastFired,
+			MessagesSinceFire: t.messagesSinceFire,
+			NextMessage:       t.nextMessage,
+		})
+	}
+	r.lock.Unlock()
+
+	if err := saveStatus(status); err != nil {
+		fmt.Printf("Failed to save timer status: %s\n", err)
+	}
+}
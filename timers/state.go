@@ -0,0 +1,127 @@
+package timers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/monktype/msc/store"
+)
+
+// The runner writes the status file; pausing and resuming (from the CLI or the API server) writes the pause file.
+const (
+	statusFile = "timers-status.json"
+	pauseFile  = "timers-paused.json"
+)
+
+// allTimers is the pause state key that applies to every timer without its own entry.
+const allTimers = "*"
+
+// Status is what the runner last reported about a timer.
+type Status struct {
+	Name              string    `json:"name"`
+	Enabled           bool      `json:"enabled"`
+	Paused            bool      `json:"paused"`
+	NextFire          time.Time `json:"next_fire"`
+	LastFired         time.Time `json:"last_fired"`
+	MessagesSinceFire int       `json:"messages_since_fire"`
+	NextMessage       int       `json:"next_message"` // Index into the timer's messages
+}
+
+// RunnerStatus is the status file: the running timers and when the runner last wrote it.
+type RunnerStatus struct {
+	ChannelID string    `json:"channel_id"`
+	Live      bool      `json:"live"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Timers    []Status  `json:"timers"`
+}
+
+// PauseState maps timer names to whether they're paused; the "*" entry applies to timers without their own entry.
+type PauseState map[string]bool
+
+// IsPaused reports whether a timer is paused.
+func (p PauseState) IsPaused(name string) bool {
+	if paused, ok := p[name]; ok {
+		return paused
+	}
+	return p[allTimers]
+}
+
+// GetStatus reads what the runner last reported. UpdatedAt is zero if the runner has never run.
+// Returns RunnerStatus and error.
+func GetStatus() (RunnerStatus, error) {
+	var status RunnerStatus
+	err := store.Load(statusFile, &status)
+	return status, err
+}
+
+func saveStatus(status RunnerStatus) error {
+	return store.Save(statusFile, status)
+}
+
+// GetPauseState reads which timers are paused.
+// Returns PauseState and error.
+func GetPauseState() (PauseState, error) {
+	state := PauseState{}
+	err := store.Load(pauseFile, &state)
+	return state, err
+}
+
+// UnknownTimerError is a name given to Pause or Resume that isn't one of the runner's timers.
+type UnknownTimerError struct {
+	Name  string
+	Known []string // The timers from the running (or last) run; empty if it hasn't run
+}
+
+func (e *UnknownTimerError) Error() string {
+	if len(e.Known) == 0 {
+		return fmt.Sprintf("there's no timer named %s; msc timers run hasn't run yet", e.Name)
+	}
+	return fmt.Sprintf("there's no timer named %s; the timers are %s", e.Name, strings.Join(e.Known, ", "))
+}
+
+// Pause pauses a timer by name, or every timer if name is empty. A running runner picks it up within a few seconds.
+// Returns error.
+func Pause(name string) error {
+	return setPaused(name, true)
+}
+
+// Resume resumes a timer by name, or every timer if name is empty.
+// Returns error.
+func Resume(name string) error {
+	return setPaused(name, false)
+}
+
+// setPaused checks a name against the timers in the runner's status, which lists every timer in its config.
+func setPaused(name string, paused bool) error {
+	if name != "" {
+		status, err := GetStatus()
+		if err != nil {
+			return err
+		}
+
+		found := false
+		var known []string
+		for _, timer := range status.Timers {
+			found = found || timer.Name == name
+			known = append(known, timer.Name)
+		}
+		if !found {
+			return &UnknownTimerError{Name: name, Known: known}
+		}
+	}
+
+	state := PauseState{}
+	return store.Update(pauseFile, &state, func() error {
+		if name == "" {
+			for key := range state {
+				delete(state, key)
+			}
+			state[allTimers] = paused
+			return nil
+		}
+
+		state[name] = paused
+		return nil
+	})
+}
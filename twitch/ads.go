@@ -1,7 +1,11 @@
 package twitch
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/nicklaw5/helix/v2"
 )
@@ -22,3 +26,93 @@ func StartCommercial(c helix.Client, channelID string, length helix.AdLengthEnum
 
 	return nil
 }
+
+// AdSchedule is the channel's ad schedule from Get Ad Schedule. Times are zero when there isn't one (not live, or no
+// ad has run yet).
+type AdSchedule struct {
+	NextAdAt        adTime `json:"next_ad_at"`
+	LastAdAt        adTime `json:"last_ad_at"`
+	Duration        int    `json:"duration"` // seconds, of the next ad
+	PrerollFreeTime int    `json:"preroll_free_time"`
+	SnoozeCount     int    `json:"snooze_count"`
+	SnoozeRefreshAt adTime `json:"snooze_refresh_at"`
+}
+
+// adTime reads the ad schedule's timestamps, which Twitch documents as RFC3339 but sends as Unix seconds (as a number
+// or a string), and as an empty string or 0 when unset.
+type adTime struct {
+	time.Time
+}
+
+func (t *adTime) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch value := raw.(type) {
+	case float64:
+		if value > 0 {
+			t.Time = time.Unix(int64(value), 0)
+		}
+	case string:
+		if value == "" || value == "0" {
+			return nil
+		}
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			t.Time = time.Unix(seconds, 0)
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		t.Time = parsed
+	}
+
+	return nil
+}
+
+// GetAdSchedule gets the channel's ad schedule. The helix library doesn't have it, so this goes around it.
+// Returns AdSchedule and error.
+func GetAdSchedule(c helix.Client, channelID string) (AdSchedule, error) {
+	var response struct {
+		Data []AdSchedule `json:"data"`
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+
+	resp, err := helixRequest(c, "GET", "/channels/ads", query, nil, &response)
+	if err != nil {
+		fmt.Printf("Getting the ad schedule failed: %s\n", err)
+		return AdSchedule{}, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return AdSchedule{}, fmt.Errorf("check status code information")
+	}
+	if len(response.Data) == 0 {
+		return AdSchedule{}, nil
+	}
+
+	return response.Data[0], nil
+}
+
+// AdBreakEvent is the event body of the channel.ad_break.begin EventSub type.
+type AdBreakEvent struct {
+	BroadcasterUserID string    `json:"broadcaster_user_id"`
+	RequesterUserID   string    `json:"requester_user_id"`
+	DurationSeconds   int       `json:"duration_seconds"`
+	StartedAt         time.Time `json:"started_at"`
+	IsAutomatic       bool      `json:"is_automatic"`
+}
+
+// SubscribeAdBreakEvents subscribes an EventSub session to the channel's ad breaks starting, both run by hand and
+// automatic.
+// Returns error.
+func SubscribeAdBreakEvents(c helix.Client, sessionID string, channelID string) error {
+	return SubscribeEventSub(c, sessionID, "channel.ad_break.begin", "1", map[string]string{
+		"broadcaster_user_id": channelID,
+	})
+}
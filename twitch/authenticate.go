@@ -76,13 +76,26 @@ func Authenticate(authType AuthType) error {
 		ResponseType: authTypeString,
 		Scopes: []string{
 			"channel:edit:commercial",
+			"channel:manage:moderators",
 			"channel:manage:polls",
 			"channel:manage:predictions",
+			"channel:manage:raids",
 			"channel:manage:redemptions",
+			"channel:manage:vips",
+			"channel:read:ads",
+			"moderation:read",
 			"moderator:manage:announcements",
+			"moderator:manage:automod",
+			"moderator:manage:automod_settings",
 			"moderator:manage:blocked_terms",
+			"moderator:manage:chat_messages",
 			"moderator:manage:chat_settings",
+			"moderator:manage:shield_mode",
 			"moderator:manage:shoutouts",
+			"moderator:manage:unban_requests",
+			"moderator:manage:warnings",
+			"user:read:chat",
+			"user:write:chat",
 		},
 		State:       state,
 		ForceVerify: false,
@@ -193,3 +206,26 @@ func RefreshToken(clientID string, clientSecret string, refreshToken string) (he
 
 	return *client, nil
 }
+
+// RequireScope checks that the current user token was granted the given scope.
+// Twitch only answers a missing scope with a generic 401, so this is for commands that want to say what's wrong up front.
+// Returns an error naming the scope if it's missing, or the validation error.
+func RequireScope(c helix.Client, scope string) error {
+	isValid, resp, err := c.ValidateToken(c.GetUserAccessToken())
+	if err != nil {
+		fmt.Printf("Token validation failed: %s\n", err)
+		return err
+	}
+	if !isValid {
+		return fmt.Errorf("token is not valid, re-authenticate with `msc authenticate`")
+	}
+
+	for _, granted := range resp.Data.Scopes {
+		if granted == scope {
+			return nil
+		}
+	}
+
+	fmt.Printf("Your token is missing the %s scope. Run `msc authenticate` again to grant it.\n", scope)
+	return fmt.Errorf("missing scope %s", scope)
+}
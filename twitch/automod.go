@@ -0,0 +1,380 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/monktype/msc/eventsub"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// AutoModSettings are the channel's AutoMod levels (0..4 each).
+// OverallLevel is nil when the levels were set per category instead of with one overall level.
+type AutoModSettings struct {
+	BroadcasterID           string `json:"broadcaster_id"`
+	ModeratorID             string `json:"moderator_id"`
+	OverallLevel            *int   `json:"overall_level"`
+	Disability              int    `json:"disability"`
+	Aggression              int    `json:"aggression"`
+	SexualitySexOrGender    int    `json:"sexuality_sex_or_gender"`
+	Misogyny                int    `json:"misogyny"`
+	Bullying                int    `json:"bullying"`
+	Swearing                int    `json:"swearing"`
+	RaceEthnicityOrReligion int    `json:"race_ethnicity_or_religion"`
+	SexBasedTerms           int    `json:"sex_based_terms"`
+}
+
+// AutoModCategories are the per-category setting names as Twitch spells them, in the order Twitch documents them.
+var AutoModCategories = []string{
+	"disability",
+	"aggression",
+	"sexuality_sex_or_gender",
+	"misogyny",
+	"bullying",
+	"swearing",
+	"race_ethnicity_or_religion",
+	"sex_based_terms",
+}
+
+// CategoryLevels returns the per-category levels keyed by the names in AutoModCategories.
+func (s AutoModSettings) CategoryLevels() map[string]int {
+	return map[string]int{
+		"disability":                 s.Disability,
+		"aggression":                 s.Aggression,
+		"sexuality_sex_or_gender":    s.SexualitySexOrGender,
+		"misogyny":                   s.Misogyny,
+		"bullying":                   s.Bullying,
+		"swearing":                   s.Swearing,
+		"race_ethnicity_or_religion": s.RaceEthnicityOrReligion,
+		"sex_based_terms":            s.SexBasedTerms,
+	}
+}
+
+type autoModSettingsResponse struct {
+	Data []AutoModSettings `json:"data"`
+}
+
+// GetAutoModSettings gets the channel's AutoMod settings.
+// Returns AutoModSettings and error.
+func GetAutoModSettings(c helix.Client, userID string, channelID string) (AutoModSettings, error) {
+	var emptySettings AutoModSettings
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("moderator_id", userID)
+
+	var settings autoModSettingsResponse
+	resp, err := helixRequest(c, "GET", "/moderation/automod/settings", query, nil, &settings)
+	if err != nil {
+		fmt.Printf("Getting AutoMod settings failed: %s\n", err)
+		return emptySettings, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptySettings, fmt.Errorf("check status code information")
+	}
+	if len(settings.Data) == 0 {
+		return emptySettings, fmt.Errorf("twitch did not return any AutoMod settings")
+	}
+
+	return settings.Data[0], nil
+}
+
+// SetAutoModOverallLevel sets every AutoMod category from one overall level (0..4).
+// Returns the resulting AutoModSettings and error.
+func SetAutoModOverallLevel(c helix.Client, userID string, channelID string, level int) (AutoModSettings, error) {
+	return updateAutoModSettings(c, userID, channelID, map[string]int{"overall_level": level})
+}
+
+// SetAutoModCategoryLevels sets AutoMod per category (0..4 each).
+// Twitch overwrites every category on update, so categories missing from levels keep their current values.
+// Returns the resulting AutoModSettings and error.
+func SetAutoModCategoryLevels(c helix.Client, userID string, channelID string, levels map[string]int) (AutoModSettings, error) {
+	current, err := GetAutoModSettings(c, userID, channelID)
+	if err != nil {
+		return AutoModSettings{}, err
+	}
+
+	body := current.CategoryLevels()
+	for name, level := range levels {
+		if _, ok := body[name]; !ok {
+			return AutoModSettings{}, fmt.Errorf("unknown AutoMod category %s", name)
+		}
+		body[name] = level
+	}
+
+	return updateAutoModSettings(c, userID, channelID, body)
+}
+
+func updateAutoModSettings(c helix.Client, userID string, channelID string, body map[string]int) (AutoModSettings, error) {
+	var emptySettings AutoModSettings
+
+	for name, level := range body {
+		if level < 0 || level > 4 {
+			return emptySettings, fmt.Errorf("AutoMod level for %s must be between 0 and 4, got %d", name, level)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("moderator_id", userID)
+
+	var settings autoModSettingsResponse
+	resp, err := helixRequest(c, "PUT", "/moderation/automod/settings", query, body, &settings)
+	if err != nil {
+		fmt.Printf("Updating AutoMod settings failed: %s\n", err)
+		return emptySettings, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptySettings, fmt.Errorf("check status code information")
+	}
+	if len(settings.Data) == 0 {
+		return emptySettings, fmt.Errorf("twitch did not return the updated AutoMod settings")
+	}
+
+	return settings.Data[0], nil
+}
+
+// CheckAutoModStatus asks Twitch whether AutoMod would allow a message in the channel.
+// Twitch only allows this with the broadcaster's own token.
+// Returns true if the message would be permitted, and error.
+func CheckAutoModStatus(c helix.Client, channelID string, text string) (bool, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+
+	body := map[string]interface{}{
+		"data": []map[string]string{
+			{"msg_id": "msc", "msg_text": text},
+		},
+	}
+
+	var result struct {
+		Data []struct {
+			MsgID       string `json:"msg_id"`
+			IsPermitted bool   `json:"is_permitted"`
+		} `json:"data"`
+	}
+
+	resp, err := helixRequest(c, "POST", "/moderation/enforcements/status", query, body, &result)
+	if err != nil {
+		fmt.Printf("Checking AutoMod status failed: %s\n", err)
+		return false, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return false, fmt.Errorf("check status code information")
+	}
+	if len(result.Data) == 0 {
+		return false, fmt.Errorf("twitch did not return an AutoMod result")
+	}
+
+	return result.Data[0].IsPermitted, nil
+}
+
+// ModerateHeldMessage allows or denies a message that AutoMod is holding for review.
+// The helix library sends this call's fields without their JSON names, so this goes around it.
+// Returns error.
+func ModerateHeldMessage(c helix.Client, userID string, messageID string, allow bool) error {
+	action := "DENY"
+	if allow {
+		action = "ALLOW"
+	}
+
+	body := map[string]string{
+		"user_id": userID,
+		"msg_id":  messageID,
+		"action":  action,
+	}
+
+	resp, err := helixRequest(c, "POST", "/moderation/automod/message", nil, body, nil)
+	if err != nil {
+		fmt.Printf("Setting held message %s to %s failed: %s\n", messageID, action, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// --- AutoMod held message queue ---
+
+// HeldMessage is a chat message AutoMod is holding for a moderator to allow or deny.
+type HeldMessage struct {
+	MessageID string    `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	UserLogin string    `json:"user_login"`
+	UserName  string    `json:"user_name"`
+	Text      string    `json:"text"`
+	Category  string    `json:"category"`
+	Level     int       `json:"level"`
+	HeldAt    time.Time `json:"held_at"`
+}
+
+type autoModHoldEvent struct {
+	UserID    string `json:"user_id"`
+	UserLogin string `json:"user_login"`
+	UserName  string `json:"user_name"`
+	MessageID string `json:"message_id"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Category string    `json:"category"`
+	Level    int       `json:"level"`
+	HeldAt   time.Time `json:"held_at"`
+	Status   string    `json:"status"` // Only on automod.message.update
+}
+
+// AutoModQueue keeps the messages AutoMod is currently holding on a channel, fed from EventSub.
+// Messages leave the queue when they're resolved through Resolve or when Twitch reports they were
+// approved, denied, or expired elsewhere (another moderator, the dashboard, or the timeout).
+type AutoModQueue struct {
+	ChannelID string
+
+	onHeld   func(HeldMessage)
+	onUpdate func(string, string)
+	lock     sync.Mutex
+	messages []HeldMessage
+	session  *eventsub.Session
+	done     chan struct{}
+	err      error
+}
+
+// StartAutoModQueue subscribes to automod.message.hold and automod.message.update for the channel and starts filling a queue.
+// The user ID is the moderator listening, which needs the moderator:manage:automod scope.
+// onHeld is called for each newly held message and onUpdate with the message ID and status when a message
+// leaves the queue from elsewhere; either can be nil.
+// Returns *AutoModQueue and error.
+func StartAutoModQueue(c helix.Client, userID string, channelID string, onHeld func(HeldMessage), onUpdate func(string, string)) (*AutoModQueue, error) {
+	session, err := eventsub.Connect()
+	if err != nil {
+		fmt.Printf("Failed to start EventSub session: %s\n", err)
+		return nil, err
+	}
+
+	condition := map[string]string{
+		"broadcaster_user_id": channelID,
+		"moderator_user_id":   userID,
+	}
+
+	for _, subscriptionType := range []string{"automod.message.hold", "automod.message.update"} {
+		err = SubscribeEventSub(c, session.ID, subscriptionType, "1", condition)
+		if err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
+
+	q := &AutoModQueue{
+		ChannelID: channelID,
+		onHeld:    onHeld,
+		onUpdate:  onUpdate,
+		session:   session,
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		err := session.Listen(q.handleNotification)
+		q.lock.Lock()
+		q.err = err
+		q.lock.Unlock()
+		close(q.done)
+	}()
+
+	return q, nil
+}
+
+func (q *AutoModQueue) handleNotification(n eventsub.Notification) {
+	var event autoModHoldEvent
+	if err := json.Unmarshal(n.Event, &event); err != nil {
+		fmt.Printf("Failed to decode %s event: %s\n", n.Type, err)
+		return
+	}
+
+	switch n.Type {
+	case "automod.message.hold":
+		held := HeldMessage{
+			MessageID: event.MessageID,
+			UserID:    event.UserID,
+			UserLogin: event.UserLogin,
+			UserName:  event.UserName,
+			Text:      event.Message.Text,
+			Category:  event.Category,
+			Level:     event.Level,
+			HeldAt:    event.HeldAt,
+		}
+
+		q.lock.Lock()
+		q.messages = append(q.messages, held)
+		q.lock.Unlock()
+
+		if q.onHeld != nil {
+			q.onHeld(held)
+		}
+	case "automod.message.update":
+		if q.remove(event.MessageID) && q.onUpdate != nil {
+			q.onUpdate(event.MessageID, event.Status)
+		}
+	}
+}
+
+// remove takes a message out of the queue; returns true if it was in it.
+func (q *AutoModQueue) remove(messageID string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for i, message := range q.messages {
+		if message.MessageID == messageID {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Messages returns a copy of the held messages, oldest first.
+func (q *AutoModQueue) Messages() []HeldMessage {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	messages := make([]HeldMessage, len(q.messages))
+	copy(messages, q.messages)
+	return messages
+}
+
+// Resolve allows or denies a held message and takes it out of the queue.
+// Returns error.
+func (q *AutoModQueue) Resolve(c helix.Client, userID string, messageID string, allow bool) error {
+	err := ModerateHeldMessage(c, userID, messageID, allow)
+	if err != nil {
+		return err
+	}
+
+	q.remove(messageID)
+	return nil
+}
+
+// Done is closed when the queue stops receiving events; Err then says why.
+func (q *AutoModQueue) Done() <-chan struct{} {
+	return q.done
+}
+
+// Err returns the error that stopped the queue, or nil if it's running or was stopped with Stop.
+func (q *AutoModQueue) Err() error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.err
+}
+
+// Stop closes the EventSub session behind the queue.
+func (q *AutoModQueue) Stop() {
+	q.session.Close()
+}
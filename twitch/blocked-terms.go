@@ -0,0 +1,184 @@
+package twitch
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// GetBlockedTerms gets every blocked term on the channel, following pagination until the end of the list.
+// Returns []helix.BlockedTerm and error.
+func GetBlockedTerms(c helix.Client, userID string, channelID string) ([]helix.BlockedTerm, error) {
+	var terms []helix.BlockedTerm
+	cursor := ""
+
+	for {
+		resp, err := c.GetBlockedTerms(&helix.BlockedTermsParams{
+			BroadcasterID: channelID,
+			ModeratorID:   userID,
+			After:         cursor,
+			First:         100, // Twitch's maximum page size
+		})
+		if err != nil {
+			fmt.Printf("Getting blocked terms failed: %s\n", err)
+			return terms, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return terms, fmt.Errorf("check status code information")
+		}
+
+		terms = append(terms, resp.Data.Terms...)
+
+		cursor = resp.Data.Pagination.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return terms, nil
+}
+
+// AddBlockedTerm adds a word or phrase (2..500 characters) to the channel's blocked terms.
+// Returns the created helix.BlockedTerm and error.
+func AddBlockedTerm(c helix.Client, userID string, channelID string, text string) (helix.BlockedTerm, error) {
+	var emptyTerm helix.BlockedTerm
+
+	resp, err := c.AddBlockedTerm(&helix.AddBlockedTermParams{
+		BroadcasterID: channelID,
+		ModeratorID:   userID,
+		Text:          text,
+	})
+	if err != nil {
+		fmt.Printf("Adding blocked term \"%s\" failed: %s\n", text, err)
+		return emptyTerm, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyTerm, fmt.Errorf("check status code information")
+	}
+	if len(resp.Data.Terms) == 0 {
+		return emptyTerm, fmt.Errorf("twitch did not return the added term")
+	}
+
+	return resp.Data.Terms[0], nil
+}
+
+// RemoveBlockedTerm removes a blocked term by its term ID.
+// The helix library sends its parameters for this call in the body instead of the query string, so this goes around it.
+// Returns error.
+func RemoveBlockedTerm(c helix.Client, userID string, channelID string, termID string) error {
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("moderator_id", userID)
+	query.Set("id", termID)
+
+	resp, err := helixRequest(c, "DELETE", "/moderation/blocked_terms", query, nil, nil)
+	if err != nil {
+		fmt.Printf("Removing blocked term %s failed: %s\n", termID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// PlanBlockedTerms works out what SyncBlockedTerms would change: the given terms that aren't blocked yet, and, if
+// removeExtra is true, the blocked terms that aren't in the given terms. Blank and repeated terms are skipped.
+// Terms are compared case-insensitively, the same way Twitch matches them in chat.
+// Returns the terms to add and the blocked terms to remove.
+func PlanBlockedTerms(current []helix.BlockedTerm, terms []string, removeExtra bool) ([]string, []helix.BlockedTerm) {
+	var add []string
+	var remove []helix.BlockedTerm
+
+	existing := make(map[string]bool)
+	for _, term := range current {
+		existing[strings.ToLower(term.Text)] = true
+	}
+
+	wanted := make(map[string]bool)
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || wanted[key] {
+			continue
+		}
+		wanted[key] = true
+
+		if !existing[key] {
+			add = append(add, term)
+		}
+	}
+
+	if removeExtra {
+		for _, term := range current {
+			if !wanted[strings.ToLower(term.Text)] {
+				remove = append(remove, term)
+			}
+		}
+	}
+
+	return add, remove
+}
+
+// CheckBlockedTermsSync refuses to sync from a list with no terms in it, which would remove every blocked term on the
+// channel; that's almost always an empty or misread file, not what was meant.
+// Returns error.
+func CheckBlockedTermsSync(terms []string) error {
+	for _, term := range terms {
+		if strings.TrimSpace(term) != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("refusing to sync from an empty list of terms; it would remove every blocked term on the channel")
+}
+
+// ApplyBlockedTerms adds and removes blocked terms as planned by PlanBlockedTerms, stopping at the first failure.
+// Returns the added terms, the removed terms, and error.
+func ApplyBlockedTerms(c helix.Client, userID string, channelID string, add []string, remove []helix.BlockedTerm) ([]string, []string, error) {
+	var added []string
+	var removed []string
+
+	for _, term := range add {
+		_, err := AddBlockedTerm(c, userID, channelID, term)
+		if err != nil {
+			return added, removed, err
+		}
+		added = append(added, term)
+	}
+
+	for _, term := range remove {
+		err := RemoveBlockedTerm(c, userID, channelID, term.ID)
+		if err != nil {
+			return added, removed, err
+		}
+		removed = append(removed, term.Text)
+	}
+
+	return added, removed, nil
+}
+
+// SyncBlockedTerms compares the given terms against the channel's current blocked terms and adds the missing ones.
+// If removeExtra is true, blocked terms on the channel that aren't in the given terms are removed too; an empty list
+// is refused then (see CheckBlockedTermsSync).
+// Returns the added terms, the removed terms, and error.
+func SyncBlockedTerms(c helix.Client, userID string, channelID string, terms []string, removeExtra bool) ([]string, []string, error) {
+	if removeExtra {
+		if err := CheckBlockedTermsSync(terms); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	current, err := GetBlockedTerms(c, userID, channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	add, remove := PlanBlockedTerms(current, terms, removeExtra)
+	return ApplyBlockedTerms(c, userID, channelID, add, remove)
+}
@@ -0,0 +1,45 @@
+package twitch
+
+import (
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// RedemptionEvent is the event body of the channel.channel_points_custom_reward_redemption.add and .update EventSub
+// types.
+type RedemptionEvent struct {
+	ID                   string `json:"id"`
+	BroadcasterUserID    string `json:"broadcaster_user_id"`
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+	BroadcasterUserName  string `json:"broadcaster_user_name"`
+	UserID               string `json:"user_id"`
+	UserLogin            string `json:"user_login"`
+	UserName             string `json:"user_name"`
+	UserInput            string `json:"user_input"`
+	Status               string `json:"status"` // unfulfilled, fulfilled, or canceled (lower case, unlike Helix)
+	Reward               struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Cost   int    `json:"cost"`
+		Prompt string `json:"prompt"`
+	} `json:"reward"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+// SubscribeRedemptionEvents subscribes an EventSub session to new redemptions of every reward on the channel.
+// Returns error.
+func SubscribeRedemptionEvents(c helix.Client, sessionID string, channelID string) error {
+	return SubscribeEventSub(c, sessionID, "channel.channel_points_custom_reward_redemption.add", "1", map[string]string{
+		"broadcaster_user_id": channelID,
+	})
+}
+
+// SubscribeRedemptionUpdateEvents subscribes an EventSub session to redemptions on the channel being fulfilled or
+// canceled, from msc, the dashboard, or anything else.
+// Returns error.
+func SubscribeRedemptionUpdateEvents(c helix.Client, sessionID string, channelID string) error {
+	return SubscribeEventSub(c, sessionID, "channel.channel_points_custom_reward_redemption.update", "1", map[string]string{
+		"broadcaster_user_id": channelID,
+	})
+}
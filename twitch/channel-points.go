@@ -2,6 +2,7 @@ package twitch
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/nicklaw5/helix/v2"
 )
@@ -83,6 +84,28 @@ func GetRewards(c helix.Client, channelID string) ([]helix.ChannelCustomReward,
 	return resp.Data.ChannelCustomRewards, nil
 }
 
+// GetManageableRewards gets only the channel's rewards that msc's client ID created, which are the only ones it can
+// change, pause, or delete.
+// Returns []helix.ChannelCustomReward and error.
+func GetManageableRewards(c helix.Client, channelID string) ([]helix.ChannelCustomReward, error) {
+	var emptyRewards []helix.ChannelCustomReward
+
+	resp, err := c.GetCustomRewards(&helix.GetCustomRewardsParams{
+		BroadcasterID:         channelID,
+		OnlyManageableRewards: true,
+	})
+	if err != nil {
+		fmt.Printf("Getting manageable channel rewards failed: %s\n", err)
+		return emptyRewards, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyRewards, fmt.Errorf("check status code information")
+	}
+
+	return resp.Data.ChannelCustomRewards, nil
+}
+
 // GetRedemptions gets Twitch custom channel points rewards' redemptions for the given channel ID, reward ID, and status.
 // Returns []helix.ChannelCustomRewardsRedemption and error.
 func GetRedemptions(c helix.Client, channelID string, rewardID string, status string) ([]helix.ChannelCustomRewardsRedemption, error) {
@@ -150,3 +173,147 @@ func FulfillRedemption(c helix.Client, channelID string, rewardID string, redemp
 
 	return resp.Data.Redemptions, nil
 }
+
+// RewardUpdate is the fields of a reward to change; nil fields are left as they are.
+// The helix library's update params send every field (and have no is_paused), so this goes around it.
+type RewardUpdate struct {
+	Title                             *string `json:"title,omitempty"`
+	Cost                              *int    `json:"cost,omitempty"`
+	Prompt                            *string `json:"prompt,omitempty"`
+	BackgroundColor                   *string `json:"background_color,omitempty"`
+	IsEnabled                         *bool   `json:"is_enabled,omitempty"`
+	IsPaused                          *bool   `json:"is_paused,omitempty"`
+	IsUserInputRequired               *bool   `json:"is_user_input_required,omitempty"`
+	IsMaxPerStreamEnabled             *bool   `json:"is_max_per_stream_enabled,omitempty"`
+	MaxPerStream                      *int    `json:"max_per_stream,omitempty"`
+	IsMaxPerUserPerStreamEnabled      *bool   `json:"is_max_per_user_per_stream_enabled,omitempty"`
+	MaxPerUserPerStream               *int    `json:"max_per_user_per_stream,omitempty"`
+	IsGlobalCooldownEnabled           *bool   `json:"is_global_cooldown_enabled,omitempty"`
+	GlobalCooldownSeconds             *int    `json:"global_cooldown_seconds,omitempty"`
+	ShouldRedemptionsSkipRequestQueue *bool   `json:"should_redemptions_skip_request_queue,omitempty"`
+}
+
+// IsEmpty reports whether the update doesn't change anything.
+func (u RewardUpdate) IsEmpty() bool {
+	return u == RewardUpdate{}
+}
+
+type customRewardsResponse struct {
+	Data []helix.ChannelCustomReward `json:"data"`
+}
+
+// UpdateReward changes a Twitch custom channel points reward in place, keeping its ID and pending redemptions.
+// Only rewards created by msc's client ID can be changed.
+// Returns the updated helix.ChannelCustomReward and error.
+func UpdateReward(c helix.Client, channelID string, rewardID string, update RewardUpdate) (helix.ChannelCustomReward, error) {
+	var emptyReward helix.ChannelCustomReward
+
+	if update.IsEmpty() {
+		return emptyReward, fmt.Errorf("nothing to update")
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("id", rewardID)
+
+	var rewards customRewardsResponse
+	resp, err := helixRequest(c, "PATCH", "/channel_points/custom_rewards", query, update, &rewards)
+	if err != nil {
+		fmt.Printf("Updating reward %s failed: %s\n", rewardID, err)
+		return emptyReward, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyReward, fmt.Errorf("check status code information")
+	}
+	if len(rewards.Data) == 0 {
+		return emptyReward, fmt.Errorf("twitch did not return the updated reward")
+	}
+
+	return rewards.Data[0], nil
+}
+
+// RedemptionBatchSize is the most redemption IDs Twitch takes in one status update.
+const RedemptionBatchSize = 50
+
+type redemptionsResponse struct {
+	Data       []helix.ChannelCustomRewardsRedemption `json:"data"`
+	Pagination helix.Pagination                       `json:"pagination"`
+}
+
+// ListRedemptions pages through a reward's redemptions with the given status, in the given sort order (OLDEST or
+// NEWEST; empty is Twitch's default, OLDEST). limit caps how many are fetched; 0 gets every one.
+// The helix library doesn't return the pagination cursor for redemptions, so this goes around it.
+// Returns []helix.ChannelCustomRewardsRedemption and error.
+func ListRedemptions(c helix.Client, channelID string, rewardID string, status string, sort string, limit int) ([]helix.ChannelCustomRewardsRedemption, error) {
+	var redemptions []helix.ChannelCustomRewardsRedemption
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		query.Set("broadcaster_id", channelID)
+		query.Set("reward_id", rewardID)
+		query.Set("status", status)
+		query.Set("first", "50") // Twitch's maximum page size
+		if sort != "" {
+			query.Set("sort", sort)
+		}
+		if cursor != "" {
+			query.Set("after", cursor)
+		}
+
+		var page redemptionsResponse
+		resp, err := helixRequest(c, "GET", "/channel_points/custom_rewards/redemptions", query, nil, &page)
+		if err != nil {
+			fmt.Printf("Getting channel reward redemptions failed: %s\n", err)
+			return redemptions, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return redemptions, fmt.Errorf("check status code information")
+		}
+
+		redemptions = append(redemptions, page.Data...)
+		if limit > 0 && len(redemptions) >= limit {
+			return redemptions[:limit], nil
+		}
+
+		cursor = page.Pagination.Cursor
+		if cursor == "" || len(page.Data) == 0 {
+			break
+		}
+	}
+
+	return redemptions, nil
+}
+
+// UpdateRedemptionStatuses sets the status (FULFILLED, or CANCELED to refund) of up to RedemptionBatchSize of a
+// reward's redemptions in one request.
+// Returns the updated []helix.ChannelCustomRewardsRedemption and error.
+func UpdateRedemptionStatuses(c helix.Client, channelID string, rewardID string, redemptionIDs []string, status string) ([]helix.ChannelCustomRewardsRedemption, error) {
+	var emptyRedemption []helix.ChannelCustomRewardsRedemption
+
+	if len(redemptionIDs) == 0 || len(redemptionIDs) > RedemptionBatchSize {
+		return emptyRedemption, fmt.Errorf("give 1 to %d redemption IDs at a time", RedemptionBatchSize)
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("reward_id", rewardID)
+	for _, id := range redemptionIDs {
+		query.Add("id", id)
+	}
+
+	var updated redemptionsResponse
+	resp, err := helixRequest(c, "PATCH", "/channel_points/custom_rewards/redemptions", query, map[string]string{"status": status}, &updated)
+	if err != nil {
+		fmt.Printf("Setting %d channel reward redemptions to %s failed: %s\n", len(redemptionIDs), status, err)
+		return emptyRedemption, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyRedemption, fmt.Errorf("check status code information")
+	}
+
+	return updated.Data, nil
+}
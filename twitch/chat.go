@@ -43,6 +43,31 @@ func SendAnnouncement(c helix.Client, userID string, channelID string, color Ann
 	return nil
 }
 
+// SendChatMessage sends a regular chat message to the channel as the current user.
+// Returns error, including when Twitch accepts the request but drops the message (AutoMod, slow mode, and so on).
+func SendChatMessage(c helix.Client, userID string, channelID string, message string) error {
+	resp, err := c.SendChatMessage(&helix.SendChatMessageParams{
+		BroadcasterID: channelID,
+		SenderID:      userID,
+		Message:       message, // Max 500 characters
+	})
+	if err != nil {
+		fmt.Printf("Chat message failed to send: %s\n", err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+	if len(resp.Data.Messages) != 0 && !resp.Data.Messages[0].IsSent {
+		reason := resp.Data.Messages[0].DropReasons.Data
+		fmt.Printf("Chat message was dropped: %s (%s)\n", reason.Message, reason.Code)
+		return fmt.Errorf("chat message was dropped")
+	}
+
+	return nil
+}
+
 func SendShoutout(c helix.Client, userID string, channelID string, targetID string) error {
 	resp, err := c.SendShoutout(&helix.SendShoutoutParams{
 		FromBroadcasterID: channelID,
@@ -61,6 +86,27 @@ func SendShoutout(c helix.Client, userID string, channelID string, targetID stri
 	return nil
 }
 
+// GetChatSettings gets the channel's chat settings (emote-only, followers-only, slow mode, and so on).
+// Returns helix.ChatSettings and error.
+func GetChatSettings(c helix.Client, channelID string) (helix.ChatSettings, error) {
+	resp, err := c.GetChatSettings(&helix.GetChatSettingsParams{
+		BroadcasterID: channelID,
+	})
+	if err != nil {
+		fmt.Printf("Getting chat settings failed: %s\n", err)
+		return helix.ChatSettings{}, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return helix.ChatSettings{}, fmt.Errorf("check status code information")
+	}
+	if len(resp.Data.Settings) == 0 {
+		return helix.ChatSettings{}, fmt.Errorf("twitch did not return any chat settings")
+	}
+
+	return resp.Data.Settings[0], nil
+}
+
 func EmoteOnly(c helix.Client, userID string, channelID string, state bool) error {
 	resp, err := c.UpdateChatSettings(&helix.UpdateChatSettingsParams{
 		ModeratorID:   userID,
@@ -196,3 +242,42 @@ func SubOnlyMode(c helix.Client, userID string, channelID string, state bool) er
 
 	return nil
 }
+
+// DeleteChatMessage removes a single chat message by message ID from the channel's chat.
+// Returns error.
+func DeleteChatMessage(c helix.Client, userID string, channelID string, messageID string) error {
+	resp, err := c.DeleteChatMessage(&helix.DeleteChatMessageParams{
+		BroadcasterID: channelID,
+		ModeratorID:   userID,
+		MessageID:     messageID,
+	})
+	if err != nil {
+		fmt.Printf("Deleting chat message %s failed: %s\n", messageID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// ClearChat removes all chat messages from the channel's chat.
+// Returns error.
+func ClearChat(c helix.Client, userID string, channelID string) error {
+	resp, err := c.DeleteAllChatMessages(&helix.DeleteAllChatMessagesParams{
+		BroadcasterID: channelID,
+		ModeratorID:   userID,
+	})
+	if err != nil {
+		fmt.Printf("Clearing chat failed: %s\n", err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// SubscribeEventSub creates an EventSub subscription delivered to a WebSocket session (see the eventsub package).
+// The condition only holds the fields the subscription type needs; the helix library's condition struct sends every
+// field, even empty ones, so this goes around it.
+// Returns error.
+func SubscribeEventSub(c helix.Client, sessionID string, subscriptionType string, version string, condition map[string]string) error {
+	body := map[string]interface{}{
+		"type":      subscriptionType,
+		"version":   version,
+		"condition": condition,
+		"transport": map[string]string{
+			"method":     "websocket",
+			"session_id": sessionID,
+		},
+	}
+
+	resp, err := helixRequest(c, "POST", "/eventsub/subscriptions", nil, body, nil)
+	if err != nil {
+		fmt.Printf("Subscribing to %s failed: %s\n", subscriptionType, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package twitch
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// WarnUser sends a warning to a user in the channel's chat; they have to acknowledge it before chatting again.
+// Returns error.
+func WarnUser(c helix.Client, userID string, channelID string, targetID string, reason string) error {
+	resp, err := c.SendModeratorWarnMessage(&helix.SendModeratorWarnChatMessageParams{
+		BroadcasterID: channelID,
+		ModeratorID:   userID,
+		Body: helix.SendModeratorWarnMessageRequestBody{
+			UserID: targetID,
+			Reason: reason,
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning user %s failed: %s\n", targetID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// UnbanRequest is a banned user's request to be unbanned from the channel.
+type UnbanRequest struct {
+	ID             string     `json:"id"`
+	BroadcasterID  string     `json:"broadcaster_id"`
+	ModeratorID    string     `json:"moderator_id"`
+	ModeratorLogin string     `json:"moderator_login"`
+	ModeratorName  string     `json:"moderator_name"`
+	UserID         string     `json:"user_id"`
+	UserLogin      string     `json:"user_login"`
+	UserName       string     `json:"user_name"`
+	Text           string     `json:"text"`
+	Status         string     `json:"status"`
+	CreatedAt      helix.Time `json:"created_at"`
+	ResolvedAt     helix.Time `json:"resolved_at"`
+	ResolutionText string     `json:"resolution_text"`
+}
+
+type unbanRequestsResponse struct {
+	Data       []UnbanRequest   `json:"data"`
+	Pagination helix.Pagination `json:"pagination"`
+}
+
+// UnbanRequestStatuses are the statuses Twitch can filter unban requests by.
+var UnbanRequestStatuses = []string{"pending", "approved", "denied", "acknowledged", "canceled"}
+
+// GetUnbanRequests gets every unban request on the channel with the given status, following pagination until the end of the list.
+// Returns []UnbanRequest and error.
+func GetUnbanRequests(c helix.Client, userID string, channelID string, status string) ([]UnbanRequest, error) {
+	var requests []UnbanRequest
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		query.Set("broadcaster_id", channelID)
+		query.Set("moderator_id", userID)
+		query.Set("status", status)
+		query.Set("first", "100") // Twitch's maximum page size
+		if cursor != "" {
+			query.Set("after", cursor)
+		}
+
+		var page unbanRequestsResponse
+		resp, err := helixRequest(c, "GET", "/moderation/unban_requests", query, nil, &page)
+		if err != nil {
+			fmt.Printf("Getting unban requests failed: %s\n", err)
+			return requests, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return requests, fmt.Errorf("check status code information")
+		}
+
+		requests = append(requests, page.Data...)
+
+		cursor = page.Pagination.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return requests, nil
+}
+
+// ResolveUnbanRequest approves or denies an unban request, with an optional resolution text shown to the user.
+// Returns the resolved UnbanRequest and error.
+func ResolveUnbanRequest(c helix.Client, userID string, channelID string, requestID string, approve bool, resolutionText string) (UnbanRequest, error) {
+	var emptyRequest UnbanRequest
+
+	status := "denied"
+	if approve {
+		status = "approved"
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelID)
+	query.Set("moderator_id", userID)
+	query.Set("unban_request_id", requestID)
+	query.Set("status", status)
+	if resolutionText != "" {
+		query.Set("resolution_text", resolutionText)
+	}
+
+	var result unbanRequestsResponse
+	resp, err := helixRequest(c, "PATCH", "/moderation/unban_requests", query, nil, &result)
+	if err != nil {
+		fmt.Printf("Setting unban request %s to %s failed: %s\n", requestID, status, err)
+		return emptyRequest, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyRequest, fmt.Errorf("check status code information")
+	}
+	if len(result.Data) == 0 {
+		return emptyRequest, fmt.Errorf("twitch did not return the resolved unban request")
+	}
+
+	return result.Data[0], nil
+}
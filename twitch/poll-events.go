@@ -0,0 +1,83 @@
+package twitch
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// PollEvent is the event body of the channel.poll.begin, channel.poll.progress, and channel.poll.end EventSub types.
+type PollEvent struct {
+	ID                   string `json:"id"`
+	BroadcasterUserID    string `json:"broadcaster_user_id"`
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+	BroadcasterUserName  string `json:"broadcaster_user_name"`
+	Title                string `json:"title"`
+	Choices              []struct {
+		ID                 string `json:"id"`
+		Title              string `json:"title"`
+		ChannelPointsVotes int    `json:"channel_points_votes"`
+		Votes              int    `json:"votes"`
+	} `json:"choices"`
+	ChannelPointsVoting struct {
+		IsEnabled     bool `json:"is_enabled"`
+		AmountPerVote int  `json:"amount_per_vote"`
+	} `json:"channel_points_voting"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`  // begin and progress only
+	Status    string    `json:"status"`   // end only: completed, archived, or terminated
+	EndedAt   time.Time `json:"ended_at"` // end only
+}
+
+// SubscribePollEvents subscribes an EventSub session to the channel's poll progress and poll end events.
+// Returns error.
+func SubscribePollEvents(c helix.Client, sessionID string, channelID string) error {
+	condition := map[string]string{"broadcaster_user_id": channelID}
+
+	for _, subscriptionType := range []string{"channel.poll.progress", "channel.poll.end"} {
+		err := SubscribeEventSub(c, sessionID, subscriptionType, "1", condition)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Poll turns the event into a helix.Poll so it can be shown and tallied like one from GetPoll.
+// Events without a status (begin and progress) are ACTIVE.
+func (e PollEvent) Poll() helix.Poll {
+	poll := helix.Poll{
+		ID:                         e.ID,
+		BroadcasterID:              e.BroadcasterUserID,
+		BroadcasterLogin:           e.BroadcasterUserLogin,
+		BroadcasterName:            e.BroadcasterUserName,
+		Title:                      e.Title,
+		ChannelPointsVotingEnabled: e.ChannelPointsVoting.IsEnabled,
+		ChannelPointsPerVote:       e.ChannelPointsVoting.AmountPerVote,
+		Status:                     "ACTIVE",
+		StartedAt:                  helix.Time{Time: e.StartedAt},
+		EndedAt:                    helix.Time{Time: e.EndedAt},
+	}
+	if e.Status != "" {
+		poll.Status = strings.ToUpper(e.Status) // The Helix API spells these in capitals.
+	}
+
+	if !e.EndsAt.IsZero() {
+		poll.Duration = int(e.EndsAt.Sub(e.StartedAt).Round(time.Second) / time.Second)
+	} else if !e.EndedAt.IsZero() {
+		poll.Duration = int(e.EndedAt.Sub(e.StartedAt).Round(time.Second) / time.Second)
+	}
+
+	for _, choice := range e.Choices {
+		poll.Choices = append(poll.Choices, helix.PollChoice{
+			ID:                 choice.ID,
+			Title:              choice.Title,
+			ChannelPointsVotes: choice.ChannelPointsVotes,
+			Votes:              choice.Votes,
+		})
+	}
+
+	return poll
+}
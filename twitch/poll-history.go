@@ -0,0 +1,193 @@
+package twitch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// PollHistoryFormats are the formats WritePollHistory can write.
+var PollHistoryFormats = []string{"csv", "json", "markdown"}
+
+// PollSummary is one poll's outcome for reviewing poll history.
+type PollSummary struct {
+	ID                 string     `json:"id"`
+	Title              string     `json:"title"`
+	Status             string     `json:"status"`
+	StartedAt          time.Time  `json:"started_at"`
+	EndedAt            time.Time  `json:"ended_at"`
+	Duration           int        `json:"duration"`
+	TotalVotes         int        `json:"total_votes"`
+	ChannelPointsVotes int        `json:"channel_points_votes"`
+	WinningVotes       int        `json:"winning_votes"`
+	Winners            []string   `json:"winners"` // More than one is a tie
+	Tie                bool       `json:"tie"`
+	Choices            []PollStat `json:"choices"`
+}
+
+// PollStat is one choice's votes in a PollSummary.
+type PollStat struct {
+	Title              string `json:"title"`
+	Votes              int    `json:"votes"`
+	ChannelPointsVotes int    `json:"channel_points_votes"`
+}
+
+// GetPollHistory pages through the channel's polls, newest first, stopping at the first poll started before since.
+// A zero since gets every poll Twitch still has (Twitch keeps them for 90 days).
+// Returns []helix.Poll and error.
+func GetPollHistory(c helix.Client, channelID string, since time.Time) ([]helix.Poll, error) {
+	var polls []helix.Poll
+	cursor := ""
+
+	for {
+		resp, err := c.GetPolls(&helix.PollsParams{
+			BroadcasterID: channelID,
+			After:         cursor,
+			First:         "20", // Twitch's maximum page size
+		})
+		if err != nil {
+			fmt.Printf("Failed to get polls on channel ID %s: %s\n", channelID, err)
+			return polls, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return polls, fmt.Errorf("check status code information")
+		}
+
+		for _, poll := range resp.Data.Polls {
+			if !since.IsZero() && poll.StartedAt.Before(since) {
+				return polls, nil
+			}
+			polls = append(polls, poll)
+		}
+
+		cursor = resp.Data.Pagination.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return polls, nil
+}
+
+// SummarizePoll works out a poll's turnout and winners; winners and ties come from PollWinners, like the poll watcher.
+func SummarizePoll(poll helix.Poll) PollSummary {
+	summary := PollSummary{
+		ID:        poll.ID,
+		Title:     poll.Title,
+		Status:    poll.Status,
+		StartedAt: poll.StartedAt.Time,
+		EndedAt:   poll.EndedAt.Time,
+		Duration:  poll.Duration,
+	}
+
+	for _, choice := range poll.Choices {
+		summary.TotalVotes += choice.Votes
+		summary.ChannelPointsVotes += choice.ChannelPointsVotes
+		summary.Choices = append(summary.Choices, PollStat{
+			Title:              choice.Title,
+			Votes:              choice.Votes,
+			ChannelPointsVotes: choice.ChannelPointsVotes,
+		})
+	}
+
+	summary.WinningVotes, summary.Winners = PollWinners(poll)
+	summary.Tie = len(summary.Winners) > 1
+
+	return summary
+}
+
+// ParseSince turns "7d", "2w", a Go duration like "36h", or a date (2006-01-02) into the time it refers to.
+// An empty string is the zero time (no limit).
+// Returns time.Time and error.
+func ParseSince(since string) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", since, time.Local); err == nil {
+		return date, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	if unit, ok := units[since[len(since)-1:]]; ok {
+		count, err := strconv.Atoi(since[:len(since)-1])
+		if err == nil && count >= 0 {
+			return time.Now().Add(-time.Duration(count) * unit), nil
+		}
+	}
+
+	duration, err := time.ParseDuration(since)
+	if err != nil || duration < 0 {
+		return time.Time{}, fmt.Errorf("can't read %q as a time; use something like 7d, 2w, 36h, or 2006-01-02", since)
+	}
+
+	return time.Now().Add(-duration), nil
+}
+
+// WritePollHistory writes poll summaries as csv, json, or markdown (a table).
+// Returns error.
+func WritePollHistory(w io.Writer, summaries []PollSummary, format string) error {
+	switch format {
+	case "json":
+		if summaries == nil {
+			summaries = []PollSummary{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summaries)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "title", "status", "started_at", "duration", "total_votes", "channel_points_votes", "winning_votes", "winners", "tie"})
+		for _, summary := range summaries {
+			writer.Write([]string{
+				summary.ID,
+				summary.Title,
+				summary.Status,
+				summary.StartedAt.Format(time.RFC3339),
+				strconv.Itoa(summary.Duration),
+				strconv.Itoa(summary.TotalVotes),
+				strconv.Itoa(summary.ChannelPointsVotes),
+				strconv.Itoa(summary.WinningVotes),
+				strings.Join(summary.Winners, "; "),
+				strconv.FormatBool(summary.Tie),
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+	case "markdown":
+		escape := strings.NewReplacer("|", "\\|", "\n", " ")
+		lines := []string{
+			"| Started | Title | Status | Votes | Channel points votes | Winner |",
+			"| --- | --- | --- | ---: | ---: | --- |",
+		}
+		for _, summary := range summaries {
+			winner := strings.Join(summary.Winners, ", ")
+			if summary.Tie {
+				winner = "Tie: " + winner
+			}
+			if summary.TotalVotes == 0 {
+				winner = "(no votes)"
+			}
+			lines = append(lines, fmt.Sprintf("| %s | %s | %s | %d | %d | %s |",
+				summary.StartedAt.Local().Format("2006-01-02 15:04"),
+				escape.Replace(summary.Title),
+				summary.Status,
+				summary.TotalVotes,
+				summary.ChannelPointsVotes,
+				escape.Replace(winner),
+			))
+		}
+		_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+		return err
+	}
+
+	return fmt.Errorf("unknown poll history format %s; use %s", format, strings.Join(PollHistoryFormats, ", "))
+}
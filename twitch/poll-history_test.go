@@ -0,0 +1,68 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		since     string
+		ago       time.Duration // How long before now the result should be, for relative times
+		date      time.Time     // The result for dates
+		zero      bool
+		expectErr bool
+	}{
+		{name: "empty", since: "", zero: true},
+		{name: "only spaces", since: "   ", zero: true},
+		{name: "days", since: "7d", ago: 7 * day},
+		{name: "weeks", since: "2w", ago: 14 * day},
+		{name: "zero days", since: "0d", ago: 0},
+		{name: "go duration", since: "36h", ago: 36 * time.Hour},
+		{name: "compound go duration", since: "1h30m", ago: 90 * time.Minute},
+		{name: "surrounding space", since: " 3d ", ago: 3 * day},
+		{name: "date", since: "2024-03-15", date: time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)},
+		{name: "negative days", since: "-1d", expectErr: true},
+		{name: "negative duration", since: "-5h", expectErr: true},
+		{name: "unknown unit", since: "3y", expectErr: true},
+		{name: "no number", since: "d", expectErr: true},
+		{name: "words", since: "last week", expectErr: true},
+		{name: "bad date", since: "2024-13-40", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := time.Now()
+			parsed, err := ParseSince(test.since)
+			after := time.Now()
+
+			if test.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", parsed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			switch {
+			case test.zero:
+				if !parsed.IsZero() {
+					t.Errorf("expected the zero time, got %v", parsed)
+				}
+			case !test.date.IsZero():
+				if !parsed.Equal(test.date) {
+					t.Errorf("got %v, expected %v", parsed, test.date)
+				}
+			default:
+				if parsed.Before(before.Add(-test.ago)) || parsed.After(after.Add(-test.ago)) {
+					t.Errorf("got %v, expected %v before now", parsed, test.ago)
+				}
+			}
+		})
+	}
+}
@@ -1,21 +1,130 @@
 package twitch
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/nicklaw5/helix/v2"
 )
 
+// Twitch's poll limits. Lengths are in characters (runes), not bytes.
+const (
+	PollTitleMaxLength      = 60
+	PollChoiceMaxLength     = 25
+	PollMinChoices          = 2
+	PollMaxChoices          = 5
+	PollMinDuration         = 15
+	PollMaxDuration         = 1800
+	PollMaxChannelPointsPer = 1000000
+)
+
+// PollViolation is one way a poll breaks Twitch's limits.
+// Field is "title", "choices", "choices[n]" (0-based), "duration", or "channel_points_per_vote".
+type PollViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// PollValidationError lists every violation in a poll, so they can all be fixed at once.
+type PollValidationError struct {
+	Violations []PollViolation `json:"violations"`
+}
+
+func (e *PollValidationError) Error() string {
+	var messages []string
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "invalid poll: " + strings.Join(messages, "; ")
+}
+
+// ValidatePoll checks a poll against Twitch's limits before it's sent.
+// Returns nil, or a *PollValidationError listing each violation.
+func ValidatePoll(title string, durationInSeconds int, options []string, channelPointsPerVote int) error {
+	var violations []PollViolation
+
+	titleLength := utf8.RuneCountInString(title)
+	if strings.TrimSpace(title) == "" {
+		violations = append(violations, PollViolation{Field: "title", Message: "the title can't be empty"})
+	} else if titleLength > PollTitleMaxLength {
+		violations = append(violations, PollViolation{Field: "title", Message: fmt.Sprintf("the title is %d characters, the maximum is %d", titleLength, PollTitleMaxLength)})
+	}
+
+	if len(options) < PollMinChoices || len(options) > PollMaxChoices {
+		violations = append(violations, PollViolation{Field: "choices", Message: fmt.Sprintf("there are %d choices, there have to be %d to %d", len(options), PollMinChoices, PollMaxChoices)})
+	}
+	for i, option := range options {
+		field := fmt.Sprintf("choices[%d]", i)
+		optionLength := utf8.RuneCountInString(option)
+		if strings.TrimSpace(option) == "" {
+			violations = append(violations, PollViolation{Field: field, Message: fmt.Sprintf("choice %d is empty", i+1)})
+		} else if optionLength > PollChoiceMaxLength {
+			violations = append(violations, PollViolation{Field: field, Message: fmt.Sprintf("choice %d (\"%s\") is %d characters, the maximum is %d", i+1, option, optionLength, PollChoiceMaxLength)})
+		}
+	}
+
+	if durationInSeconds < PollMinDuration || durationInSeconds > PollMaxDuration {
+		violations = append(violations, PollViolation{Field: "duration", Message: fmt.Sprintf("the duration is %d seconds, it has to be %d to %d", durationInSeconds, PollMinDuration, PollMaxDuration)})
+	}
+
+	if channelPointsPerVote < 0 || channelPointsPerVote > PollMaxChannelPointsPer {
+		violations = append(violations, PollViolation{Field: "channel_points_per_vote", Message: fmt.Sprintf("channel points per vote is %d, it has to be 0 to %d", channelPointsPerVote, PollMaxChannelPointsPer)})
+	}
+
+	if len(violations) != 0 {
+		return &PollValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// TruncatePoll shortens the title and choices to Twitch's length limits, cutting by character so multi-byte
+// text and emoji stay intact. Other limits (choice count, duration) are left to ValidatePoll.
+// Returns the title, the choices, and a note for each thing that was shortened.
+func TruncatePoll(title string, options []string) (string, []string, []string) {
+	var notes []string
+
+	if utf8.RuneCountInString(title) > PollTitleMaxLength {
+		shortened := truncateRunes(title, PollTitleMaxLength)
+		notes = append(notes, fmt.Sprintf("title shortened to \"%s\"", shortened))
+		title = shortened
+	}
+
+	truncated := make([]string, len(options))
+	for i, option := range options {
+		truncated[i] = option
+		if utf8.RuneCountInString(option) > PollChoiceMaxLength {
+			truncated[i] = truncateRunes(option, PollChoiceMaxLength)
+			notes = append(notes, fmt.Sprintf("choice %d shortened to \"%s\"", i+1, truncated[i]))
+		}
+	}
+
+	return title, truncated, notes
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit]))
+}
+
 // CreatePoll creates a Twitch poll with the given title, duration, and options.
-// Returns a poll ID and error.
-func CreatePoll(c helix.Client, channelID string, title string, durationInSeconds int, options []string) (string, error) {
+// channelPointsPerVote above 0 lets viewers buy extra votes for that many channel points each; 0 turns that off.
+// The poll is checked with ValidatePoll first.
+// Returns a poll ID and error (a *PollValidationError if the poll breaks Twitch's limits).
+func CreatePoll(c helix.Client, channelID string, title string, durationInSeconds int, options []string, channelPointsPerVote int) (string, error) {
+	if err := ValidatePoll(title, durationInSeconds, options, channelPointsPerVote); err != nil {
+		return "", err
+	}
+
 	// Convert options to a slice of PollChoiceParam
 	var pollChoices []helix.PollChoiceParam
 	for _, option := range options {
-		if len(option) > 25 {
-			fmt.Printf("Warning: Option '%s' exceeds the maximum length of 25 characters.\n", option)
-			option = option[:25] // Truncate if necessary
-		}
 		pollChoices = append(pollChoices, helix.PollChoiceParam{Title: option})
 	}
 
@@ -24,6 +133,9 @@ func CreatePoll(c helix.Client, channelID string, title string, durationInSecond
 		Title:         title,
 		Choices:       pollChoices,
 		Duration:      durationInSeconds,
+
+		ChannelPointsVotingEnabled: channelPointsPerVote > 0,
+		ChannelPointsPerVote:       channelPointsPerVote,
 	})
 	if err != nil {
 		fmt.Printf("Creating a poll failed: %s\n", err)
@@ -85,17 +197,60 @@ func GetPoll(c helix.Client, channelID string, pollID string) (helix.Poll, error
 	return polls.Data.Polls[0], nil
 }
 
+// PollWinners finds the choice(s) with the most votes; more than one is a tie.
+// Returns the winning vote count and the winning choices' titles, in the poll's order.
+func PollWinners(poll helix.Poll) (int, []string) {
+	maxVotes := 0
+	var winningOptions []string
+
+	for _, option := range poll.Choices {
+		if option.Votes > maxVotes {
+			maxVotes = option.Votes
+			winningOptions = []string{option.Title}
+		} else if option.Votes == maxVotes {
+			winningOptions = append(winningOptions, option.Title)
+		}
+	}
+
+	return maxVotes, winningOptions
+}
+
+// BreakTie picks one of the tied options uniformly at random, using crypto/rand so nobody can predict or steer it.
+// Returns the picked option and error.
+func BreakTie(options []string) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("no options to pick from")
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(options))))
+	if err != nil {
+		return "", fmt.Errorf("failed to pick a random option: %s", err)
+	}
+
+	return options[n.Int64()], nil
+}
+
 // EndPoll terminates a poll.
 // Takes a Client, the string of the channel ID, and the string of the poll ID.
 // Returns error.
 func EndPoll(c helix.Client, channelID string, pollID string) error {
+	return endPoll(c, channelID, pollID, "TERMINATED")
+}
+
+// ArchivePoll ends a poll and hides its results from the channel, where EndPoll leaves them showing.
+// Returns error.
+func ArchivePoll(c helix.Client, channelID string, pollID string) error {
+	return endPoll(c, channelID, pollID, "ARCHIVED")
+}
+
+func endPoll(c helix.Client, channelID string, pollID string, status string) error {
 	resp, err := c.EndPoll(&helix.EndPollParams{
 		BroadcasterID: channelID,
 		ID:            pollID,
-		Status:        "TERMINATED",
+		Status:        status,
 	})
 	if err != nil {
-		fmt.Printf("Failed to terminate poll: %s\n", err)
+		fmt.Printf("Failed to set poll to %s: %s\n", status, err)
 		return err
 	}
 	if resp.StatusCode >= 300 {
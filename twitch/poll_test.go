@@ -0,0 +1,223 @@
+package twitch
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidatePoll(t *testing.T) {
+	tests := []struct {
+		name                 string
+		title                string
+		duration             int
+		options              []string
+		channelPointsPerVote int
+		fields               []string // Fields of the expected violations, in order; nil for a valid poll
+	}{
+		{
+			name:     "valid",
+			title:    "Best snack?",
+			duration: 60,
+			options:  []string{"Chips", "Popcorn"},
+		},
+		{
+			name:                 "valid at every limit",
+			title:                strings.Repeat("t", PollTitleMaxLength),
+			duration:             PollMaxDuration,
+			options:              []string{strings.Repeat("a", PollChoiceMaxLength), "b", "c", "d", "e"},
+			channelPointsPerVote: PollMaxChannelPointsPer,
+		},
+		{
+			name:     "multi-byte title counted by character",
+			title:    strings.Repeat("é", PollTitleMaxLength),
+			duration: PollMinDuration,
+			options:  []string{"🍕", "🌮"},
+		},
+		{
+			name:     "blank title",
+			title:    "   ",
+			duration: 60,
+			options:  []string{"Yes", "No"},
+			fields:   []string{"title"},
+		},
+		{
+			name:     "long title",
+			title:    strings.Repeat("t", PollTitleMaxLength+1),
+			duration: 60,
+			options:  []string{"Yes", "No"},
+			fields:   []string{"title"},
+		},
+		{
+			name:     "too few choices",
+			title:    "Poll",
+			duration: 60,
+			options:  []string{"Only"},
+			fields:   []string{"choices"},
+		},
+		{
+			name:     "too many choices",
+			title:    "Poll",
+			duration: 60,
+			options:  []string{"a", "b", "c", "d", "e", "f"},
+			fields:   []string{"choices"},
+		},
+		{
+			name:     "empty and long choices",
+			title:    "Poll",
+			duration: 60,
+			options:  []string{"", strings.Repeat("b", PollChoiceMaxLength+1)},
+			fields:   []string{"choices[0]", "choices[1]"},
+		},
+		{
+			name:     "short duration",
+			title:    "Poll",
+			duration: PollMinDuration - 1,
+			options:  []string{"Yes", "No"},
+			fields:   []string{"duration"},
+		},
+		{
+			name:     "long duration",
+			title:    "Poll",
+			duration: PollMaxDuration + 1,
+			options:  []string{"Yes", "No"},
+			fields:   []string{"duration"},
+		},
+		{
+			name:                 "negative channel points",
+			title:                "Poll",
+			duration:             60,
+			options:              []string{"Yes", "No"},
+			channelPointsPerVote: -1,
+			fields:               []string{"channel_points_per_vote"},
+		},
+		{
+			name:                 "too many channel points",
+			title:                "Poll",
+			duration:             60,
+			options:              []string{"Yes", "No"},
+			channelPointsPerVote: PollMaxChannelPointsPer + 1,
+			fields:               []string{"channel_points_per_vote"},
+		},
+		{
+			name:                 "everything wrong at once",
+			title:                "",
+			duration:             0,
+			options:              []string{" "},
+			channelPointsPerVote: -5,
+			fields:               []string{"title", "choices", "choices[0]", "duration", "channel_points_per_vote"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidatePoll(test.title, test.duration, test.options, test.channelPointsPerVote)
+			if test.fields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *PollValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *PollValidationError, got %v", err)
+			}
+
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if !slices.Equal(fields, test.fields) {
+				t.Errorf("violations on %v, expected %v", fields, test.fields)
+			}
+		})
+	}
+}
+
+func TestTruncatePoll(t *testing.T) {
+	tests := []struct {
+		name          string
+		title         string
+		options       []string
+		expectTitle   string
+		expectOptions []string
+		expectNotes   int
+	}{
+		{
+			name:          "nothing to shorten",
+			title:         "Best snack?",
+			options:       []string{"Chips", "Popcorn"},
+			expectTitle:   "Best snack?",
+			expectOptions: []string{"Chips", "Popcorn"},
+		},
+		{
+			name:          "exactly at the limits",
+			title:         strings.Repeat("t", PollTitleMaxLength),
+			options:       []string{strings.Repeat("a", PollChoiceMaxLength)},
+			expectTitle:   strings.Repeat("t", PollTitleMaxLength),
+			expectOptions: []string{strings.Repeat("a", PollChoiceMaxLength)},
+		},
+		{
+			name:          "long title",
+			title:         strings.Repeat("t", PollTitleMaxLength+10),
+			options:       []string{"Yes", "No"},
+			expectTitle:   strings.Repeat("t", PollTitleMaxLength),
+			expectOptions: []string{"Yes", "No"},
+			expectNotes:   1,
+		},
+		{
+			name:          "long choice only",
+			title:         "Poll",
+			options:       []string{"Yes", strings.Repeat("n", PollChoiceMaxLength+1)},
+			expectTitle:   "Poll",
+			expectOptions: []string{"Yes", strings.Repeat("n", PollChoiceMaxLength)},
+			expectNotes:   1,
+		},
+		{
+			name:          "emoji cut by character",
+			title:         "Poll",
+			options:       []string{strings.Repeat("🍕", PollChoiceMaxLength+3)},
+			expectTitle:   "Poll",
+			expectOptions: []string{strings.Repeat("🍕", PollChoiceMaxLength)},
+			expectNotes:   1,
+		},
+		{
+			name:          "trailing space at the cut is trimmed",
+			title:         "Poll",
+			options:       []string{strings.Repeat("a", PollChoiceMaxLength-1) + " tail"},
+			expectTitle:   "Poll",
+			expectOptions: []string{strings.Repeat("a", PollChoiceMaxLength-1)},
+			expectNotes:   1,
+		},
+		{
+			name:          "title and every choice",
+			title:         strings.Repeat("t", PollTitleMaxLength+1),
+			options:       []string{strings.Repeat("a", PollChoiceMaxLength+1), strings.Repeat("b", PollChoiceMaxLength+1)},
+			expectTitle:   strings.Repeat("t", PollTitleMaxLength),
+			expectOptions: []string{strings.Repeat("a", PollChoiceMaxLength), strings.Repeat("b", PollChoiceMaxLength)},
+			expectNotes:   3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := slices.Clone(test.options)
+
+			title, options, notes := TruncatePoll(test.title, test.options)
+			if title != test.expectTitle {
+				t.Errorf("title %q, expected %q", title, test.expectTitle)
+			}
+			if !slices.Equal(options, test.expectOptions) {
+				t.Errorf("choices %q, expected %q", options, test.expectOptions)
+			}
+			if len(notes) != test.expectNotes {
+				t.Errorf("%d notes (%q), expected %d", len(notes), notes, test.expectNotes)
+			}
+			if !slices.Equal(test.options, original) {
+				t.Errorf("the given choices were changed to %q", test.options)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// CreatePrediction creates a Twitch prediction with the given title, prediction window, and outcomes.
+// Returns a prediction ID and error.
+func CreatePrediction(c helix.Client, channelID string, title string, windowInSeconds int, outcomes []string) (string, error) {
+	var predictionOutcomes []helix.PredictionChoiceParam
+	for _, outcome := range outcomes {
+		predictionOutcomes = append(predictionOutcomes, helix.PredictionChoiceParam{Title: outcome})
+	}
+
+	prediction, err := c.CreatePrediction(&helix.CreatePredictionParams{
+		BroadcasterID:    channelID,
+		Title:            title,
+		Outcomes:         predictionOutcomes,
+		PredictionWindow: windowInSeconds,
+	})
+	if err != nil {
+		fmt.Printf("Creating a prediction failed: %s\n", err)
+		return "", err
+	}
+	if prediction.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", prediction)
+		return "", fmt.Errorf("check status code information")
+	}
+
+	fmt.Printf("Prediction created with ID: %s\n", prediction.Data.Predictions[0].ID)
+	return prediction.Data.Predictions[0].ID, nil
+}
+
+// GetPredictions gets the most recent predictions from a channel ID.
+func GetPredictions(c helix.Client, channelID string) ([]helix.Prediction, error) {
+	var emptyPredictionResponse []helix.Prediction
+
+	predictions, err := c.GetPredictions(&helix.PredictionsParams{
+		BroadcasterID: channelID,
+	})
+	if err != nil {
+		fmt.Printf("Failed to get predictions on channel ID %s: %s\n", channelID, err)
+		return emptyPredictionResponse, err
+	}
+	if predictions.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", predictions)
+		return emptyPredictionResponse, fmt.Errorf("check status code information")
+	}
+
+	return predictions.Data.Predictions, nil
+}
+
+// GetPrediction gets a prediction from a prediction ID string and a channel ID string.
+// Returns an empty prediction (ID "") if it isn't found, like GetPoll.
+func GetPrediction(c helix.Client, channelID string, predictionID string) (helix.Prediction, error) {
+	var emptyPredictionResponse helix.Prediction
+
+	predictions, err := c.GetPredictions(&helix.PredictionsParams{
+		BroadcasterID: channelID,
+		ID:            predictionID,
+	})
+	if err != nil {
+		fmt.Printf("Failed to get prediction on channel ID %s: %s\n", channelID, err)
+		return emptyPredictionResponse, err
+	}
+	if predictions.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", predictions)
+		return emptyPredictionResponse, fmt.Errorf("check status code information")
+	}
+	if len(predictions.Data.Predictions) == 0 {
+		fmt.Printf("Prediction %s was not found on channel %s.\n", predictionID, channelID)
+		return emptyPredictionResponse, nil
+	}
+
+	return predictions.Data.Predictions[0], nil
+}
+
+// EndPrediction locks, resolves, or cancels a prediction. Status is "LOCKED", "RESOLVED", or "CANCELED";
+// the winning outcome ID is only for "RESOLVED".
+// The helix library always sends winning_outcome_id, even empty, so this goes around it.
+// Returns the resulting helix.Prediction and error.
+func EndPrediction(c helix.Client, channelID string, predictionID string, status string, winningOutcomeID string) (helix.Prediction, error) {
+	var emptyPredictionResponse helix.Prediction
+
+	body := map[string]string{
+		"broadcaster_id": channelID,
+		"id":             predictionID,
+		"status":         status,
+	}
+	if winningOutcomeID != "" {
+		body["winning_outcome_id"] = winningOutcomeID
+	}
+
+	var predictions helix.ManyPredictions
+	resp, err := helixRequest(c, "PATCH", "/predictions", nil, body, &predictions)
+	if err != nil {
+		fmt.Printf("Failed to set prediction to %s: %s\n", status, err)
+		return emptyPredictionResponse, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return emptyPredictionResponse, fmt.Errorf("check status code information")
+	}
+	if len(predictions.Predictions) == 0 {
+		return emptyPredictionResponse, fmt.Errorf("twitch did not return the updated prediction")
+	}
+
+	return predictions.Predictions[0], nil
+}
+
+// LockPrediction closes a prediction to new predictions early.
+func LockPrediction(c helix.Client, channelID string, predictionID string) (helix.Prediction, error) {
+	return EndPrediction(c, channelID, predictionID, "LOCKED", "")
+}
+
+// ResolvePrediction pays out a prediction to the winning outcome.
+func ResolvePrediction(c helix.Client, channelID string, predictionID string, winningOutcomeID string) (helix.Prediction, error) {
+	return EndPrediction(c, channelID, predictionID, "RESOLVED", winningOutcomeID)
+}
+
+// CancelPrediction cancels a prediction and refunds everyone's channel points.
+func CancelPrediction(c helix.Client, channelID string, predictionID string) (helix.Prediction, error) {
+	return EndPrediction(c, channelID, predictionID, "CANCELED", "")
+}
@@ -0,0 +1,53 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// StartRaid starts a raid from the channel to the target. Twitch shows a 90-second countdown first,
+// which the broadcaster can skip with "Raid Now" or stop with CancelRaid.
+// Returns error.
+func StartRaid(c helix.Client, channelID string, targetID string) error {
+	if err := RequireScope(c, "channel:manage:raids"); err != nil {
+		return err
+	}
+
+	resp, err := c.StartRaid(&helix.StartRaidParams{
+		FromBroadcasterID: channelID,
+		ToBroadcasterID:   targetID,
+	})
+	if err != nil {
+		fmt.Printf("Raid failed to start: %s\n", err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// CancelRaid cancels the channel's pending raid.
+// Returns error.
+func CancelRaid(c helix.Client, channelID string) error {
+	if err := RequireScope(c, "channel:manage:raids"); err != nil {
+		return err
+	}
+
+	resp, err := c.CancelRaid(&helix.CancelRaidParams{
+		BroadcasterID: channelID,
+	})
+	if err != nil {
+		fmt.Printf("Raid failed to cancel: %s\n", err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/monktype/msc/keys"
+	"github.com/nicklaw5/helix/v2"
+)
+
+const helixBaseURL = "https://api.twitch.tv/helix"
+
+// helixRequest sends a request to a Helix endpoint that the helix library doesn't implement (or doesn't implement correctly).
+// The query is added to the URL, the body (if not nil) is sent as JSON, and a successful response is decoded into out (if not nil).
+// Returns the status code and error information in a helix.ResponseCommon so it can be handled like the library's responses, and error.
+func helixRequest(c helix.Client, method string, path string, query url.Values, body interface{}, out interface{}) (helix.ResponseCommon, error) {
+	var common helix.ResponseCommon
+
+	// The library doesn't expose the client ID it was created with, so grab it the same way GetClient does.
+	clientID, err := keys.GetKey("client-id")
+	if err != nil {
+		return common, err
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return common, err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	requestURL := helixBaseURL + path
+	if len(query) != 0 {
+		requestURL = requestURL + "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, requestURL, bodyReader)
+	if err != nil {
+		return common, err
+	}
+	req.Header.Set("Client-ID", clientID)
+	req.Header.Set("Authorization", "Bearer "+c.GetUserAccessToken())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return common, err
+	}
+	defer resp.Body.Close()
+
+	common.StatusCode = resp.StatusCode
+	common.Header = resp.Header
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return common, err
+	}
+
+	if resp.StatusCode >= 300 {
+		// Twitch sends {"error": ..., "status": ..., "message": ...} on failures; keep it for the caller to print.
+		_ = json.Unmarshal(respBody, &common)
+		return common, nil
+	}
+
+	if out != nil && len(respBody) != 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return common, fmt.Errorf("failed to decode response from %s: %s", path, err)
+		}
+	}
+
+	return common, nil
+}
@@ -0,0 +1,147 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// GetModerators gets every moderator on the channel, following pagination until the end of the list.
+// Returns []helix.Moderator and error.
+func GetModerators(c helix.Client, channelID string) ([]helix.Moderator, error) {
+	var moderators []helix.Moderator
+	cursor := ""
+
+	for {
+		resp, err := c.GetModerators(&helix.GetModeratorsParams{
+			BroadcasterID: channelID,
+			After:         cursor,
+			First:         100, // Twitch's maximum page size
+		})
+		if err != nil {
+			fmt.Printf("Getting moderators failed: %s\n", err)
+			return moderators, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return moderators, fmt.Errorf("check status code information")
+		}
+
+		moderators = append(moderators, resp.Data.Moderators...)
+
+		cursor = resp.Data.Pagination.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return moderators, nil
+}
+
+// AddModerator makes a user a moderator on the channel. Twitch removes the user's VIP status if they have it.
+// Returns error.
+func AddModerator(c helix.Client, channelID string, targetID string) error {
+	resp, err := c.AddChannelModerator(&helix.AddChannelModeratorParams{
+		BroadcasterID: channelID,
+		UserID:        targetID,
+	})
+	if err != nil {
+		fmt.Printf("Adding moderator %s failed: %s\n", targetID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// RemoveModerator removes a user's moderator status on the channel.
+// Returns error.
+func RemoveModerator(c helix.Client, channelID string, targetID string) error {
+	resp, err := c.RemoveChannelModerator(&helix.RemoveChannelModeratorParams{
+		BroadcasterID: channelID,
+		UserID:        targetID,
+	})
+	if err != nil {
+		fmt.Printf("Removing moderator %s failed: %s\n", targetID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// GetVIPs gets every VIP on the channel, following pagination until the end of the list.
+// Returns []helix.ChannelVips and error.
+func GetVIPs(c helix.Client, channelID string) ([]helix.ChannelVips, error) {
+	var vips []helix.ChannelVips
+	cursor := ""
+
+	for {
+		resp, err := c.GetChannelVips(&helix.GetChannelVipsParams{
+			BroadcasterID: channelID,
+			After:         cursor,
+			First:         100, // Twitch's maximum page size
+		})
+		if err != nil {
+			fmt.Printf("Getting VIPs failed: %s\n", err)
+			return vips, err
+		}
+		if resp.StatusCode >= 300 {
+			fmt.Printf("Status code was bad: %v\n", resp)
+			return vips, fmt.Errorf("check status code information")
+		}
+
+		vips = append(vips, resp.Data.ChannelsVips...)
+
+		cursor = resp.Data.Pagination.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return vips, nil
+}
+
+// AddVIP makes a user a VIP on the channel. Twitch refuses this for moderators.
+// Returns error.
+func AddVIP(c helix.Client, channelID string, targetID string) error {
+	resp, err := c.AddChannelVip(&helix.AddChannelVipParams{
+		BroadcasterID: channelID,
+		UserID:        targetID,
+	})
+	if err != nil {
+		fmt.Printf("Adding VIP %s failed: %s\n", targetID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
+
+// RemoveVIP removes a user's VIP status on the channel.
+// Returns error.
+func RemoveVIP(c helix.Client, channelID string, targetID string) error {
+	resp, err := c.RemoveChannelVip(&helix.RemoveChannelVipParams{
+		BroadcasterID: channelID,
+		UserID:        targetID,
+	})
+	if err != nil {
+		fmt.Printf("Removing VIP %s failed: %s\n", targetID, err)
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return fmt.Errorf("check status code information")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package twitch
+
+import (
+	"fmt"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// GetChannelInformation gets the channel's name, category (game), and title; it works whether or not the channel is live.
+// Returns helix.ChannelInformation and error.
+func GetChannelInformation(c helix.Client, channelID string) (helix.ChannelInformation, error) {
+	resp, err := c.GetChannelInformation(&helix.GetChannelInformationParams{
+		BroadcasterIDs: []string{channelID},
+	})
+	if err != nil {
+		fmt.Printf("Getting channel information failed: %s\n", err)
+		return helix.ChannelInformation{}, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return helix.ChannelInformation{}, fmt.Errorf("check status code information")
+	}
+	if len(resp.Data.Channels) == 0 {
+		return helix.ChannelInformation{}, fmt.Errorf("channel %s not found", channelID)
+	}
+
+	return resp.Data.Channels[0], nil
+}
+
+// GetStream gets the channel's live stream.
+// Returns helix.Stream, true if the channel is live, and error.
+func GetStream(c helix.Client, channelID string) (helix.Stream, bool, error) {
+	resp, err := c.GetStreams(&helix.StreamsParams{
+		UserIDs: []string{channelID},
+		Type:    "live",
+	})
+	if err != nil {
+		fmt.Printf("Getting stream failed: %s\n", err)
+		return helix.Stream{}, false, err
+	}
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Status code was bad: %v\n", resp)
+		return helix.Stream{}, false, fmt.Errorf("check status code information")
+	}
+	if len(resp.Data.Streams) == 0 {
+		return helix.Stream{}, false, nil
+	}
+
+	return resp.Data.Streams[0], true, nil
+}
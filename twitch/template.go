@@ -0,0 +1,210 @@
+package twitch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// AnnouncementMaxLength is the most characters Twitch accepts in one announcement; it truncates anything longer.
+const AnnouncementMaxLength = 500
+
+// DefaultAnnouncementDelay is the wait between announcements that were split from one message, so they arrive in order.
+const DefaultAnnouncementDelay = 2 * time.Second
+
+// TemplateVariables are the channel variables RenderTemplate fills in, written in text as {name}.
+var TemplateVariables = []string{"channel", "game", "title", "uptime", "viewer_count", "date"}
+
+var templateVariablePattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// TemplateData is the live channel and stream information that templates are filled from.
+type TemplateData struct {
+	Channel     string
+	Game        string
+	Title       string
+	Live        bool
+	StartedAt   time.Time
+	ViewerCount int
+}
+
+// GetTemplateData gets the channel information, and the stream if the channel is live, for filling in templates.
+// Returns TemplateData and error.
+func GetTemplateData(c helix.Client, channelID string) (TemplateData, error) {
+	info, err := GetChannelInformation(c, channelID)
+	if err != nil {
+		return TemplateData{}, err
+	}
+
+	data := TemplateData{
+		Channel: info.BroadcasterName,
+		Game:    info.GameName,
+		Title:   info.Title,
+	}
+
+	stream, live, err := GetStream(c, channelID)
+	if err != nil {
+		return TemplateData{}, err
+	}
+	if live {
+		data.Live = true
+		data.StartedAt = stream.StartedAt
+		data.ViewerCount = stream.ViewerCount
+	}
+
+	return data, nil
+}
+
+// Values returns the template variables keyed by name; {uptime} is "offline" when the channel isn't live.
+func (d TemplateData) Values() map[string]string {
+	uptime := "offline"
+	if d.Live {
+		uptime = formatUptime(time.Since(d.StartedAt))
+	}
+
+	return map[string]string{
+		"channel":      d.Channel,
+		"game":         d.Game,
+		"title":        d.Title,
+		"uptime":       uptime,
+		"viewer_count": strconv.Itoa(d.ViewerCount),
+		"date":         time.Now().Format("2006-01-02"),
+	}
+}
+
+func formatUptime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", hours, minutes)
+}
+
+// UsesTemplateVariables reports whether text has any of the channel variables in TemplateVariables,
+// so callers can skip looking the channel up when there's nothing to fill in.
+func UsesTemplateVariables(text string) bool {
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(text, -1) {
+		for _, name := range TemplateVariables {
+			if match[1] == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FillTemplate replaces every {name} in text that has an entry in values; unknown names are left as they are.
+func FillTemplate(text string, values map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(text, func(variable string) string {
+		if value, ok := values[variable[1:len(variable)-1]]; ok {
+			return value
+		}
+		return variable
+	})
+}
+
+// RenderTemplate fills in text's channel variables from live data, plus any extra variables the caller has
+// (for example a poll's title); extra values win over channel ones with the same name.
+// The channel is only looked up if text uses one of its variables.
+// Returns the rendered string and error.
+func RenderTemplate(c helix.Client, channelID string, text string, extra map[string]string) (string, error) {
+	values := make(map[string]string)
+
+	if UsesTemplateVariables(text) {
+		data, err := GetTemplateData(c, channelID)
+		if err != nil {
+			return "", err
+		}
+		values = data.Values()
+	}
+
+	for name, value := range extra {
+		values[name] = value
+	}
+
+	return FillTemplate(text, values), nil
+}
+
+// RenderAnnouncements renders text as a template and turns it into the announcements to send, in order:
+// every non-blank line is its own announcement, and lines over AnnouncementMaxLength are split on word boundaries.
+// Returns []string and error.
+func RenderAnnouncements(c helix.Client, channelID string, text string, extra map[string]string) ([]string, error) {
+	rendered, err := RenderTemplate(c, channelID, text, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	var announcements []string
+	for _, line := range strings.Split(rendered, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		announcements = append(announcements, SplitMessage(line, AnnouncementMaxLength)...)
+	}
+
+	return announcements, nil
+}
+
+// SplitMessage splits message into parts of at most limit characters (runes, not bytes), breaking at the last
+// space that fits; a word longer than limit is broken wherever it has to be. A limit of 0 or less leaves the message
+// whole.
+func SplitMessage(message string, limit int) []string {
+	var parts []string
+
+	runes := []rune(strings.TrimSpace(message))
+	if limit <= 0 {
+		if len(runes) != 0 {
+			parts = append(parts, string(runes))
+		}
+		return parts
+	}
+
+	for len(runes) > limit {
+		cut := limit
+		for i := limit; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+
+		part := strings.TrimSpace(string(runes[:cut]))
+		if part != "" {
+			parts = append(parts, part)
+		}
+		runes = []rune(strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace))
+	}
+	if len(runes) != 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
+
+// SendAnnouncements sends each announcement in order, waiting delay between them so they show up in order in chat.
+// Returns error, stopping at the first announcement that fails.
+func SendAnnouncements(c helix.Client, userID string, channelID string, color AnnouncementColor, announcements []string, delay time.Duration) error {
+	for i, announcement := range announcements {
+		if i > 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+
+		err := SendAnnouncement(c, userID, channelID, color, announcement)
+		if err != nil {
+			if len(announcements) > 1 {
+				fmt.Printf("Stopped after sending %d of %d announcements.\n", i, len(announcements))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
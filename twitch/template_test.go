@@ -0,0 +1,137 @@
+package twitch
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		limit   int
+		expect  []string
+	}{
+		{
+			name:    "empty",
+			message: "",
+			limit:   10,
+			expect:  nil,
+		},
+		{
+			name:    "only spaces",
+			message: "   \n\t ",
+			limit:   10,
+			expect:  nil,
+		},
+		{
+			name:    "fits",
+			message: "hello world",
+			limit:   20,
+			expect:  []string{"hello world"},
+		},
+		{
+			name:    "exactly the limit",
+			message: "hello world",
+			limit:   11,
+			expect:  []string{"hello world"},
+		},
+		{
+			name:    "surrounding space trimmed",
+			message: "  hello  ",
+			limit:   10,
+			expect:  []string{"hello"},
+		},
+		{
+			name:    "breaks at the last space that fits",
+			message: "one two three four",
+			limit:   9,
+			expect:  []string{"one two", "three", "four"},
+		},
+		{
+			name:    "space right at the limit",
+			message: "abcde fghij",
+			limit:   5,
+			expect:  []string{"abcde", "fghij"},
+		},
+		{
+			name:    "runs of spaces dropped at the break",
+			message: "abc      def",
+			limit:   5,
+			expect:  []string{"abc", "def"},
+		},
+		{
+			name:    "word longer than the limit",
+			message: "abcdefghij",
+			limit:   4,
+			expect:  []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:    "long word after a short one",
+			message: "hi abcdefghij",
+			limit:   5,
+			expect:  []string{"hi", "abcde", "fghij"},
+		},
+		{
+			name:    "counted in runes",
+			message: "héllo wörld",
+			limit:   5,
+			expect:  []string{"héllo", "wörld"},
+		},
+		{
+			name:    "zero limit leaves it whole",
+			message: " hello world ",
+			limit:   0,
+			expect:  []string{"hello world"},
+		},
+		{
+			name:    "negative limit leaves it whole",
+			message: "hello world",
+			limit:   -1,
+			expect:  []string{"hello world"},
+		},
+		{
+			name:    "zero limit and empty",
+			message: "",
+			limit:   0,
+			expect:  nil,
+		},
+		{
+			name:    "emoji not split",
+			message: "🍕🍕🍕🍕🍕🍕",
+			limit:   4,
+			expect:  []string{"🍕🍕🍕🍕", "🍕🍕"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts := SplitMessage(test.message, test.limit)
+			if !slices.Equal(parts, test.expect) {
+				t.Fatalf("split into %q, expected %q", parts, test.expect)
+			}
+			for _, part := range parts {
+				if test.limit > 0 && utf8.RuneCountInString(part) > test.limit {
+					t.Errorf("part %q is over the limit of %d", part, test.limit)
+				}
+				if !utf8.ValidString(part) {
+					t.Errorf("part %q isn't valid UTF-8", part)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitMessageAnnouncementLimit(t *testing.T) {
+	message := strings.Repeat("word ", 200)
+
+	parts := SplitMessage(message, AnnouncementMaxLength)
+	if len(parts) < 2 {
+		t.Fatalf("expected a %d character message to be split, got %d parts", len(message), len(parts))
+	}
+	if strings.Join(parts, " ") != strings.TrimSpace(message) {
+		t.Errorf("joining the parts back doesn't give the message")
+	}
+}
@@ -98,6 +98,10 @@ func GetUserID(c helix.Client, username string) (string, error) {
 		fmt.Printf("Failed to get user %s: %s\n", username, err)
 		return "", err
 	}
+	if len(resp.Data.Users) == 0 {
+		fmt.Printf("User %s was not found.\n", username)
+		return "", fmt.Errorf("user %s not found", username)
+	}
 
 	return resp.Data.Users[0].ID, nil
 }